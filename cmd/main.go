@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/Dzikuri/openidea-segokuning/internal/config"
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/pkgerrors"
 )
@@ -13,6 +14,13 @@ func main() {
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
 	logger := zerolog.New(os.Stdout)
 
+	// NOTE Refuse to start without a signing secret or key
+	jwtConfig, err := helper.LoadJwtConfig()
+	if err != nil {
+		logger.Fatal().Msg(fmt.Sprintf("JWT configuration error: %s", err.Error()))
+	}
+	helper.SetJwtConfig(jwtConfig)
+
 	// Call function connection database
 	db, err := config.NewDatabase()
 	if err != nil {
@@ -0,0 +1,2 @@
+-- Drop Table Friend Requests
+DROP TABLE IF EXISTS "public"."friend_requests";
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- NOTE Create table friend_requests
+CREATE TABLE IF NOT EXISTS "public"."friend_requests" (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "sender_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "receiver_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "status" varchar(20) NOT NULL DEFAULT 'pending' CHECK ("status" IN ('pending', 'accepted', 'rejected', 'cancelled')),
+    "created_at" timestamptz(6),
+    "updated_at" timestamptz(6)
+);
+
+-- NOTE Index columns
+CREATE INDEX IF NOT EXISTS idx_friend_requests_sender_id ON "public"."friend_requests" ("sender_id", "status");
+
+CREATE INDEX IF NOT EXISTS idx_friend_requests_receiver_id ON "public"."friend_requests" ("receiver_id", "status");
+
+-- NOTE Only one pending request between two users, whichever side sent it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friend_requests_pending_pair ON "public"."friend_requests" (LEAST("sender_id", "receiver_id"), GREATEST("sender_id", "receiver_id"))
+WHERE
+    "status" = 'pending';
@@ -0,0 +1,2 @@
+-- Drop Table User Blocks
+DROP TABLE IF EXISTS "public"."user_blocks";
\ No newline at end of file
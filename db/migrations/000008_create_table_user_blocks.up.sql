@@ -0,0 +1,13 @@
+-- NOTE Create table user_blocks, a block also hides content and stops friendship, a mute only hides content
+CREATE TABLE IF NOT EXISTS "public"."user_blocks" (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "blocked_user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "type" varchar(10) NOT NULL DEFAULT 'block' CHECK ("type" IN ('block', 'mute')),
+    "created_at" timestamptz(6),
+    "updated_at" timestamptz(6),
+    UNIQUE ("user_id", "blocked_user_id")
+);
+
+-- NOTE Index columns
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked_user_id ON "public"."user_blocks" ("blocked_user_id");
@@ -0,0 +1,6 @@
+ALTER TABLE
+    posts
+ADD
+    COLUMN IF NOT EXISTS visibility varchar(10) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'friends', 'only_me'));
+
+CREATE INDEX IF NOT EXISTS idx_posts_visibility ON posts (visibility);
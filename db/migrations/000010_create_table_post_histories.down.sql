@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_histories;
\ No newline at end of file
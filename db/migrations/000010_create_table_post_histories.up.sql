@@ -0,0 +1,11 @@
+-- Create Table, every row is a version of a post replaced by an edit
+CREATE TABLE IF NOT EXISTS post_histories (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "post_id" uuid NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    "content" text NOT NULL,
+    "tags" text [] NOT NULL,
+    "created_at" timestamptz(6),
+    "replaced_at" timestamptz(6)
+);
+
+CREATE INDEX IF NOT EXISTS "idx_post_histories_post_id" ON "public"."post_histories"("post_id", "replaced_at");
@@ -0,0 +1,2 @@
+ALTER TABLE
+    post_comments DROP COLUMN IF EXISTS deleted_at;
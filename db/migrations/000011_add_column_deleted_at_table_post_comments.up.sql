@@ -0,0 +1,4 @@
+ALTER TABLE
+    post_comments
+ADD
+    COLUMN IF NOT EXISTS deleted_at timestamptz(6);
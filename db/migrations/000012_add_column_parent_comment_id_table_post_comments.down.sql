@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_post_comment_parent_comment_id;
+
+ALTER TABLE
+    post_comments DROP COLUMN IF EXISTS parent_comment_id,
+    DROP COLUMN IF EXISTS depth;
@@ -0,0 +1,8 @@
+ALTER TABLE
+    post_comments
+ADD
+    COLUMN IF NOT EXISTS parent_comment_id uuid REFERENCES post_comments(id) ON DELETE CASCADE,
+ADD
+    COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS "idx_post_comment_parent_comment_id" ON "public"."post_comments"("parent_comment_id");
@@ -0,0 +1,24 @@
+-- Create Table, a reaction targets either a post or a comment
+CREATE TABLE IF NOT EXISTS reactions (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "post_id" uuid REFERENCES posts(id) ON DELETE CASCADE,
+    "comment_id" uuid REFERENCES post_comments(id) ON DELETE CASCADE,
+    "type" varchar(10) NOT NULL CHECK ("type" IN ('like', 'love', 'haha', 'wow', 'sad', 'angry')),
+    "created_at" timestamptz(6),
+    "updated_at" timestamptz(6),
+    CHECK (("post_id" IS NULL) <> ("comment_id" IS NULL))
+);
+
+-- NOTE One reaction per user and target
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reactions_user_post" ON "public"."reactions"("user_id", "post_id")
+WHERE
+    "post_id" IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reactions_user_comment" ON "public"."reactions"("user_id", "comment_id")
+WHERE
+    "comment_id" IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "idx_reactions_post_id" ON "public"."reactions"("post_id", "type");
+
+CREATE INDEX IF NOT EXISTS "idx_reactions_comment_id" ON "public"."reactions"("comment_id", "type");
@@ -0,0 +1,22 @@
+-- NOTE Create table sessions, one per login
+CREATE TABLE IF NOT EXISTS "public"."sessions" (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "revoked_at" timestamptz(6),
+    "created_at" timestamptz(6),
+    "updated_at" timestamptz(6)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON "public"."sessions" ("user_id");
+
+-- NOTE Create table refresh_tokens, only the sha256 of a token is stored
+CREATE TABLE IF NOT EXISTS "public"."refresh_tokens" (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "session_id" uuid NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    "token_hash" varchar(64) NOT NULL UNIQUE,
+    "used_at" timestamptz(6),
+    "expires_at" timestamptz(6) NOT NULL,
+    "created_at" timestamptz(6)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON "public"."refresh_tokens" ("session_id");
@@ -0,0 +1,8 @@
+ALTER TABLE
+    sessions
+ADD
+    COLUMN IF NOT EXISTS user_agent text NOT NULL DEFAULT '',
+ADD
+    COLUMN IF NOT EXISTS ip_address varchar(45) NOT NULL DEFAULT '',
+ADD
+    COLUMN IF NOT EXISTS last_seen_at timestamptz(6);
@@ -0,0 +1,9 @@
+-- NOTE Create table login_attempts, key is a credential or an ip address
+CREATE TABLE IF NOT EXISTS "public"."login_attempts" (
+    "key" varchar(320) NOT NULL PRIMARY KEY,
+    "failures" integer NOT NULL DEFAULT 0,
+    "last_failed_at" timestamptz(6),
+    "locked_until" timestamptz(6)
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failed_at ON "public"."login_attempts" ("last_failed_at");
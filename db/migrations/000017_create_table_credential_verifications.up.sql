@@ -0,0 +1,17 @@
+-- NOTE Create table credential_verifications, a credential waiting for its one-time code
+CREATE TABLE IF NOT EXISTS "public"."credential_verifications" (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "credential_type" varchar(10) NOT NULL CHECK ("credential_type" IN ('email', 'phone')),
+    "credential_value" varchar(255) NOT NULL,
+    "code_hash" varchar(64) NOT NULL,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "expires_at" timestamptz(6) NOT NULL,
+    "consumed_at" timestamptz(6),
+    "created_at" timestamptz(6)
+);
+
+-- NOTE Only one pending verification per user and credential type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credential_verifications_pending ON "public"."credential_verifications" ("user_id", "credential_type")
+WHERE
+    "consumed_at" IS NULL;
@@ -0,0 +1,11 @@
+-- NOTE Create table password_resets, only the sha256 of a reset token is stored
+CREATE TABLE IF NOT EXISTS "public"."password_resets" (
+    "id" uuid NOT NULL DEFAULT uuid_generate_v4() PRIMARY KEY,
+    "user_id" uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "token_hash" varchar(64) NOT NULL UNIQUE,
+    "expires_at" timestamptz(6) NOT NULL,
+    "used_at" timestamptz(6),
+    "created_at" timestamptz(6)
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_resets_user_id ON "public"."password_resets" ("user_id");
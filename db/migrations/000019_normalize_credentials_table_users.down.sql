@@ -0,0 +1 @@
+-- NOTE Normalizing credentials cannot be undone, the original formatting is not kept
@@ -0,0 +1,65 @@
+-- NOTE Refuse to run while two users would end up with the same email or phone once normalized,
+-- handlers only look up normalized values from now on so a skipped row could never log in again.
+-- Merge or fix the listed accounts by hand, then run the migration again.
+DO $$
+DECLARE
+    collisions text;
+BEGIN
+    SELECT
+        string_agg(duplicated.email, ', ') INTO collisions
+    FROM
+        (
+            SELECT
+                lower(trim(email)) AS email
+            FROM
+                users
+            WHERE
+                email IS NOT NULL
+            GROUP BY
+                lower(trim(email))
+            HAVING
+                count(*) > 1
+        ) AS duplicated;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'users share an email once case-folded: %', collisions;
+    END IF;
+
+    SELECT
+        string_agg(duplicated.phone, ', ') INTO collisions
+    FROM
+        (
+            SELECT
+                regexp_replace(regexp_replace(trim(phone), '[ .()-]', '', 'g'), '^00', '+') AS phone
+            FROM
+                users
+            WHERE
+                phone IS NOT NULL
+            GROUP BY
+                regexp_replace(regexp_replace(trim(phone), '[ .()-]', '', 'g'), '^00', '+')
+            HAVING
+                count(*) > 1
+        ) AS duplicated;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'users share a phone once formatting is stripped: %', collisions;
+    END IF;
+END $$;
+
+-- NOTE Case-fold emails
+UPDATE
+    users
+SET
+    email = lower(trim(email))
+WHERE
+    email IS NOT NULL
+    AND email <> lower(trim(email));
+
+-- NOTE Strip phone formatting and turn a 00 prefix into +, same as helper.NormalizePhone
+UPDATE
+    users
+SET
+    phone = regexp_replace(regexp_replace(trim(phone), '[ .()-]', '', 'g'), '^00', '+')
+WHERE
+    phone IS NOT NULL
+    AND phone <> regexp_replace(regexp_replace(trim(phone), '[ .()-]', '', 'g'), '^00', '+');
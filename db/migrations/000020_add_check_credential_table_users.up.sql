@@ -0,0 +1,8 @@
+-- NOTE A user always keeps at least one credential to log in with
+ALTER TABLE
+    users
+ADD
+    CONSTRAINT users_credential_check CHECK (
+        email IS NOT NULL
+        OR phone IS NOT NULL
+    );
@@ -0,0 +1,9 @@
+ALTER TABLE
+    users
+ADD
+    COLUMN IF NOT EXISTS deleted_at timestamptz(6);
+
+-- NOTE The purge only looks at deleted accounts
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE
+    credential_verifications DROP COLUMN IF EXISTS sent_at;
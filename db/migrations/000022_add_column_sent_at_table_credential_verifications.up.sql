@@ -0,0 +1,12 @@
+-- NOTE created_at starts the attempt window, sent_at is when the latest code was sent
+ALTER TABLE
+    credential_verifications
+ADD
+    COLUMN IF NOT EXISTS sent_at timestamptz(6);
+
+UPDATE
+    credential_verifications
+SET
+    sent_at = created_at
+WHERE
+    sent_at IS NULL;
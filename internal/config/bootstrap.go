@@ -1,11 +1,15 @@
 package config
 
 import (
+	"context"
 	"database/sql"
+	"os"
+	"time"
 
 	"github.com/Dzikuri/openidea-segokuning/internal/delivery/handler"
 	"github.com/Dzikuri/openidea-segokuning/internal/delivery/middleware"
 	"github.com/Dzikuri/openidea-segokuning/internal/delivery/routes"
+	"github.com/Dzikuri/openidea-segokuning/internal/notifier"
 	"github.com/Dzikuri/openidea-segokuning/internal/repository"
 	"github.com/Dzikuri/openidea-segokuning/internal/usecase"
 	"github.com/labstack/echo/v4"
@@ -26,7 +30,34 @@ func Bootstrap(config *BootstrapConfig) {
 
 	postRepository := repository.NewPostRepository(config.DB)
 
-	UseCase := usecase.NewUseCase(*config.Logger, userRepository, friendRepository, postRepository)
+	blockRepository := repository.NewBlockRepository(config.DB)
+
+	reactionRepository := repository.NewReactionRepository(config.DB)
+
+	sessionRepository := repository.NewSessionRepository(config.DB)
+
+	// NOTE Failed logins are shared through Postgres unless LOGIN_ATTEMPT_STORE=memory
+	loginAttemptRepository := repository.NewLoginAttemptRepository(config.DB)
+	if os.Getenv("LOGIN_ATTEMPT_STORE") == "memory" {
+		loginAttemptRepository = repository.NewLoginAttemptMemoryRepository()
+	}
+
+	verificationRepository := repository.NewVerificationRepository(config.DB)
+
+	passwordResetRepository := repository.NewPasswordResetRepository(config.DB)
+
+	accountRepository := repository.NewAccountRepository(config.DB)
+
+	// NOTE Notifications are only logged (or written to NOTIFIER_FILE) until a provider is configured
+	var notify notifier.Notifier = notifier.NewLogNotifier(*config.Logger)
+	if path := os.Getenv("NOTIFIER_FILE"); path != "" {
+		notify = notifier.NewFileNotifier(path)
+	}
+
+	UseCase := usecase.NewUseCase(*config.Logger, userRepository, friendRepository, postRepository, blockRepository, reactionRepository, sessionRepository, loginAttemptRepository, verificationRepository, passwordResetRepository, accountRepository, notify)
+
+	// NOTE Deleted accounts are purged once their grace period is over
+	UseCase.StartAccountPurge(context.Background(), time.Hour)
 
 	middleware := middleware.NewMiddleware(config.Logger, UseCase)
 
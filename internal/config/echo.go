@@ -1,6 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog"
@@ -12,6 +17,13 @@ func NewEcho(logger *zerolog.Logger) *echo.Echo {
 
 	e.HideBanner = true
 
+	// NOTE The client ip is used by the login throttle and stored on sessions, so forwarded headers are only trusted from TRUSTED_PROXIES
+	ipExtractor, err := NewIPExtractor(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		logger.Fatal().Msg(fmt.Sprintf("TRUSTED_PROXIES configuration error: %s", err.Error()))
+	}
+	e.IPExtractor = ipExtractor
+
 	// e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
@@ -29,3 +41,30 @@ func NewEcho(logger *zerolog.Logger) *echo.Echo {
 
 	return e
 }
+
+// NewIPExtractor returns how the client ip is read from a request. Without trusted proxies it is the
+// address of the connection, otherwise X-Forwarded-For is read for requests coming from one of the
+// comma separated CIDR ranges in trustedProxies.
+func NewIPExtractor(trustedProxies string) (echo.IPExtractor, error) {
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+
+	for _, cidr := range strings.Split(trustedProxies, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, want a CIDR range", cidr)
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	if len(options) == 3 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) DeleteAccount(c echo.Context) error {
+	var request model.UserDeleteRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.Id = usr.Id
+	}
+
+	result, err := h.UseCase.DeleteAccount(c.Request().Context(), &request)
+	if err != nil {
+
+		var locked *model.LoginLockedError
+		if errors.As(err, &locked) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+				Code:    http.StatusTooManyRequests,
+				Message: locked.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrPasswordNotMatch) {
+			return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
+				Code:    echo.ErrBadRequest.Code,
+				Message: model.ErrPasswordNotMatch.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrUserNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrUserNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    result,
+		Message: "Account deleted",
+	})
+}
+
+func (h *Handler) ExportAccount(c echo.Context) error {
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, model.ResponseError{
+			Code:    http.StatusUnauthorized,
+			Message: model.ErrUnauthorize.Error(),
+		})
+	}
+
+	result, err := h.UseCase.ExportAccount(c.Request().Context(), usr.Id.String())
+	if err != nil {
+
+		if errors.Is(err, model.ErrUserNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrUserNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	// NOTE Browsers save the archive instead of rendering it
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="account-export.json"`)
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    result,
+		Message: "Success",
+	})
+}
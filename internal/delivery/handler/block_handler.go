@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) BlockUser(c echo.Context) error {
+	var request model.BlockRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if !ok {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+	request.BlockedUserId = request.UserId
+	request.UserId = usr.Id.String()
+
+	if request.BlockedUserId == request.UserId {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: "Cannot Block Yourself",
+			Error:   err,
+		})
+	}
+
+	result, err := h.UseCase.BlockUser(c.Request().Context(), request)
+	if err != nil {
+
+		if errors.Is(err, model.ErrUserNotFound) || errors.Is(err, model.ErrResNotFound.Error) {
+			return c.JSON(model.ErrResNotFound.Code, model.ResponseError{
+				Code:    model.ErrResNotFound.Code,
+				Message: model.ErrUserNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: echo.ErrInternalServerError.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Message: http.StatusText(http.StatusOK),
+		Data:    result,
+	})
+}
+
+func (h *Handler) UnblockUser(c echo.Context) error {
+	var request model.BlockRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if !ok {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+	request.BlockedUserId = request.UserId
+	request.UserId = usr.Id.String()
+
+	err = h.UseCase.UnblockUser(c.Request().Context(), request)
+	if err != nil {
+
+		if errors.Is(err, model.ErrNotBlocked) {
+			return c.JSON(model.ErrResNotFound.Code, model.ResponseError{
+				Code:    model.ErrResNotFound.Code,
+				Message: model.ErrNotBlocked.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: echo.ErrInternalServerError.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Message: http.StatusText(http.StatusOK),
+		Data:    make(map[string]interface{}),
+	})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) UserReplaceCredential(c echo.Context) error {
+	var request model.UserReplaceCredentialRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	request.CredentialValue = helper.NormalizeCredential(request.CredentialType, request.CredentialValue)
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.Id = usr.Id
+	}
+
+	result, err := h.UseCase.UserReplaceCredential(c.Request().Context(), &request)
+	if err != nil {
+		return credentialErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    result,
+		Message: "Verification code sent",
+	})
+}
+
+func (h *Handler) UserRemoveCredential(c echo.Context) error {
+	var request model.UserRemoveCredentialRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.Id = usr.Id
+	}
+
+	err = h.UseCase.UserRemoveCredential(c.Request().Context(), &request)
+	if err != nil {
+		return credentialErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    map[string]interface{}{},
+		Message: "Credential removed",
+	})
+}
+
+func credentialErrorResponse(c echo.Context, err error) error {
+
+	var locked *model.LoginLockedError
+	if errors.As(err, &locked) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+			Code:    http.StatusTooManyRequests,
+			Message: locked.Error(),
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrPasswordNotMatch) || errors.Is(err, model.ErrCredentialNotLinked) || errors.Is(err, model.ErrCredentialUnchanged) || errors.Is(err, model.ErrLastCredential) {
+		return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
+			Code:    echo.ErrBadRequest.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrVerificationResendTooSoon) {
+		return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+			Code:    http.StatusTooManyRequests,
+			Message: model.ErrVerificationResendTooSoon.Error(),
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrUserAlreadyExists) {
+		return c.JSON(echo.ErrConflict.Code, model.ResponseError{
+			Code:    echo.ErrConflict.Code,
+			Message: model.ErrUserAlreadyExists.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+		Code:    echo.ErrInternalServerError.Code,
+		Message: err.Error(),
+		Error:   err,
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
@@ -53,7 +54,7 @@ func (h *Handler) CreateFriend(c echo.Context) error {
 		})
 	}
 
-	_, err = h.UseCase.AddFriend(c.Request().Context(), request.UserId, request.FriendId)
+	result, err := h.UseCase.SendFriendRequest(c.Request().Context(), request.UserId, request.FriendId)
 
 	if err != nil {
 
@@ -92,6 +93,22 @@ func (h *Handler) CreateFriend(c echo.Context) error {
 			})
 		}
 
+		if condition := errors.Is(err, model.ErrBlocked); condition {
+			return c.JSON(model.ErrResForbidden.Code, model.ResponseError{
+				Code:    model.ErrResForbidden.Code,
+				Message: model.ErrBlocked.Error(),
+				Error:   err,
+			})
+		}
+
+		if condition := errors.Is(err, model.ErrFriendRequestPending); condition {
+			return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+				Code:    model.ErrResBadRequest.Code,
+				Message: model.ErrFriendRequestPending.Error(),
+				Error:   err,
+			})
+		}
+
 		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
 			Code:    echo.ErrInternalServerError.Code,
 			Message: echo.ErrInternalServerError.Error(),
@@ -102,7 +119,7 @@ func (h *Handler) CreateFriend(c echo.Context) error {
 	return c.JSON(http.StatusOK, model.Response[any]{
 		Code:    http.StatusOK,
 		Message: http.StatusText(http.StatusOK),
-		Data:    make(map[string]interface{}),
+		Data:    result,
 	})
 }
 
@@ -362,3 +379,105 @@ func (h *Handler) DeleteFriend(c echo.Context) error {
 		Data:    make(map[string]interface{}),
 	})
 }
+
+func (h *Handler) GetFriendRequests(c echo.Context) error {
+	var request model.GetFriendRequestListRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if !ok {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+	request.UserId = usr.Id.String()
+
+	result, err := h.UseCase.GetFriendRequestList(c.Request().Context(), request)
+	if err != nil {
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: echo.ErrInternalServerError.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) AcceptFriendRequest(c echo.Context) error {
+	return h.friendRequestAction(c, h.UseCase.AcceptFriendRequest)
+}
+
+func (h *Handler) RejectFriendRequest(c echo.Context) error {
+	return h.friendRequestAction(c, h.UseCase.RejectFriendRequest)
+}
+
+func (h *Handler) CancelFriendRequest(c echo.Context) error {
+	return h.friendRequestAction(c, h.UseCase.CancelFriendRequest)
+}
+
+// friendRequestAction runs one of the accept/reject/cancel usecases for the friend request in the path.
+func (h *Handler) friendRequestAction(c echo.Context, action func(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error)) error {
+	request := model.FriendRequestActionRequest{
+		Id: c.Param("requestId"),
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if !ok {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+		})
+	}
+	request.UserId = usr.Id.String()
+
+	result, err := action(c.Request().Context(), request)
+	if err != nil {
+
+		if errors.Is(err, model.ErrFriendRequestNotFound) {
+			return c.JSON(model.ErrResNotFound.Code, model.ResponseError{
+				Code:    model.ErrResNotFound.Code,
+				Message: model.ErrFriendRequestNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrFriendRequestNotPending) {
+			return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+				Code:    model.ErrResBadRequest.Code,
+				Message: model.ErrFriendRequestNotPending.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: echo.ErrInternalServerError.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Message: http.StatusText(http.StatusOK),
+		Data:    result,
+	})
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) UserChangePassword(c echo.Context) error {
+	var request model.UserChangePasswordRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.Id = usr.Id
+	}
+	request.SessionId, _ = c.Get("sessionId").(string)
+
+	err = h.UseCase.UserChangePassword(c.Request().Context(), &request)
+	if err != nil {
+
+		var locked *model.LoginLockedError
+		if errors.As(err, &locked) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+				Code:    http.StatusTooManyRequests,
+				Message: locked.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrPasswordNotMatch) {
+			return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
+				Code:    echo.ErrBadRequest.Code,
+				Message: model.ErrPasswordNotMatch.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    map[string]interface{}{},
+		Message: "Password changed",
+	})
+}
+
+func (h *Handler) UserForgotPassword(c echo.Context) error {
+	var request model.UserForgotPasswordRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	request.CredentialValue = helper.NormalizeCredential(request.CredentialType, request.CredentialValue)
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = h.UseCase.UserForgotPassword(c.Request().Context(), &request)
+	if err != nil {
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	// NOTE Same response whether or not the credential belongs to a user
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    map[string]interface{}{},
+		Message: "If the account exists, a reset token has been sent",
+	})
+}
+
+func (h *Handler) UserResetPassword(c echo.Context) error {
+	var request model.UserResetPasswordRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = h.UseCase.UserResetPassword(c.Request().Context(), &request)
+	if err != nil {
+
+		if errors.Is(err, model.ErrInvalidPasswordReset) {
+			return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
+				Code:    echo.ErrBadRequest.Code,
+				Message: model.ErrInvalidPasswordReset.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrUserNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrUserNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    map[string]interface{}{},
+		Message: "Password reset",
+	})
+}
@@ -79,6 +79,11 @@ func (h *Handler) GetPosts(c echo.Context) error {
 		})
 	}
 
+	// NOTE Offset is optional when paginating with a cursor
+	if offset == "" && request.Cursor != "" {
+		offset = "0"
+	}
+
 	offsetInt, err := strconv.Atoi(offset)
 	if err != nil {
 		// Handle error (e.g., invalid parameter format)
@@ -92,6 +97,11 @@ func (h *Handler) GetPosts(c echo.Context) error {
 	request.Limit = limitInt
 	request.Offset = offsetInt
 
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.UserId = usr.Id.String()
+	}
+
 	err = request.Validate()
 	if err != nil {
 		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
@@ -104,6 +114,14 @@ func (h *Handler) GetPosts(c echo.Context) error {
 	res, err := h.UseCase.PostList(c.Request().Context(), &request)
 
 	if err != nil {
+		if errors.Is(err, model.ErrInvalidCursor) {
+			return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+				Code:    model.ErrResBadRequest.Code,
+				Message: model.ErrInvalidCursor.Error(),
+				Error:   err,
+			})
+		}
+
 		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
 			Code:    echo.ErrInternalServerError.Code,
 			Message: err.Error(),
@@ -151,6 +169,30 @@ func (h *Handler) CreatePostComment(c echo.Context) error {
 			})
 		}
 
+		if errors.Is(err, model.ErrCommentNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrCommentNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrCommentDepthExceeded) {
+			return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
+				Code:    echo.ErrBadRequest.Code,
+				Message: model.ErrCommentDepthExceeded.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrBlocked) {
+			return c.JSON(echo.ErrForbidden.Code, model.ResponseError{
+				Code:    echo.ErrForbidden.Code,
+				Message: model.ErrBlocked.Error(),
+				Error:   err,
+			})
+		}
+
 		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
 			Code:    echo.ErrInternalServerError.Code,
 			Message: err.Error(),
@@ -165,3 +207,331 @@ func (h *Handler) CreatePostComment(c echo.Context) error {
 	})
 
 }
+
+func (h *Handler) UpdatePost(c echo.Context) error {
+
+	var request model.UpdatePostRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	request.Id = c.Param("postId")
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.UserId = usr.Id.String()
+	}
+
+	result, err := h.UseCase.PostUpdate(c.Request().Context(), &request)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Post updated",
+		Code:    http.StatusOK,
+		Data:    result,
+	})
+}
+
+func (h *Handler) DeletePost(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	err := h.UseCase.PostDelete(c.Request().Context(), c.Param("postId"), userId)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Post deleted",
+		Code:    http.StatusOK,
+		Data:    make(map[string]interface{}),
+	})
+}
+
+func (h *Handler) GetPost(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	result, err := h.UseCase.PostDetail(c.Request().Context(), c.Param("postId"), userId)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Ok",
+		Code:    http.StatusOK,
+		Data:    result,
+	})
+}
+
+func (h *Handler) GetPostHistory(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	result, err := h.UseCase.PostHistory(c.Request().Context(), c.Param("postId"), userId)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Ok",
+		Code:    http.StatusOK,
+		Data:    result,
+	})
+}
+
+func (h *Handler) GetPostComments(c echo.Context) error {
+
+	var request model.PostCommentListRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	request.PostId = c.Param("postId")
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.UserId = usr.Id.String()
+	}
+
+	res, err := h.UseCase.PostCommentList(c.Request().Context(), &request)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidCursor) {
+			return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+				Code:    model.ErrResBadRequest.Code,
+				Message: model.ErrInvalidCursor.Error(),
+				Error:   err,
+			})
+		}
+
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) UpdatePostComment(c echo.Context) error {
+
+	var request model.UpdatePostCommentRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	request.Id = c.Param("commentId")
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.UserId = usr.Id.String()
+	}
+
+	result, err := h.UseCase.PostUpdateComment(c.Request().Context(), &request)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Comment updated",
+		Code:    http.StatusOK,
+		Data:    result,
+	})
+}
+
+func (h *Handler) DeletePostComment(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	err := h.UseCase.PostDeleteComment(c.Request().Context(), c.Param("commentId"), userId)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Comment deleted",
+		Code:    http.StatusOK,
+		Data:    make(map[string]interface{}),
+	})
+}
+
+// postErrorResponse maps errors of the single post and comment usecases to a response.
+func postErrorResponse(c echo.Context, err error) error {
+
+	if errors.Is(err, model.ErrResNotFound.Error) {
+		return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+			Code:    echo.ErrNotFound.Code,
+			Message: "Post Not Found",
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrCommentNotFound) {
+		return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+			Code:    echo.ErrNotFound.Code,
+			Message: model.ErrCommentNotFound.Error(),
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrReactionNotFound) {
+		return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+			Code:    echo.ErrNotFound.Code,
+			Message: model.ErrReactionNotFound.Error(),
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrForbidden) {
+		return c.JSON(echo.ErrForbidden.Code, model.ResponseError{
+			Code:    echo.ErrForbidden.Code,
+			Message: model.ErrResForbidden.Message,
+			Error:   err,
+		})
+	}
+
+	if errors.Is(err, model.ErrBlocked) {
+		return c.JSON(echo.ErrForbidden.Code, model.ResponseError{
+			Code:    echo.ErrForbidden.Code,
+			Message: model.ErrBlocked.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+		Code:    echo.ErrInternalServerError.Code,
+		Message: err.Error(),
+		Error:   err,
+	})
+}
+
+func (h *Handler) ReactToPost(c echo.Context) error {
+	return h.react(c, model.ReactionRequest{PostId: c.Param("postId")})
+}
+
+func (h *Handler) ReactToComment(c echo.Context) error {
+	return h.react(c, model.ReactionRequest{CommentId: c.Param("commentId")})
+}
+
+func (h *Handler) RemovePostReaction(c echo.Context) error {
+	return h.removeReaction(c, model.ReactionRequest{PostId: c.Param("postId")})
+}
+
+func (h *Handler) RemoveCommentReaction(c echo.Context) error {
+	return h.removeReaction(c, model.ReactionRequest{CommentId: c.Param("commentId")})
+}
+
+// react adds or changes the caller's reaction on the post or comment set in target.
+func (h *Handler) react(c echo.Context, target model.ReactionRequest) error {
+
+	var request model.ReactionRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	request.PostId = target.PostId
+	request.CommentId = target.CommentId
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.UserId = usr.Id.String()
+	}
+
+	result, err := h.UseCase.React(c.Request().Context(), request)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Reaction saved",
+		Code:    http.StatusOK,
+		Data:    result,
+	})
+}
+
+// removeReaction drops the caller's reaction on the post or comment set in request.
+func (h *Handler) removeReaction(c echo.Context, request model.ReactionRequest) error {
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.UserId = usr.Id.String()
+	}
+
+	err := h.UseCase.RemoveReaction(c.Request().Context(), request)
+	if err != nil {
+		return postErrorResponse(c, err)
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Message: "Reaction removed",
+		Code:    http.StatusOK,
+		Data:    make(map[string]interface{}),
+	})
+}
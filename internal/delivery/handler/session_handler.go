@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handler) RefreshToken(c echo.Context) error {
+	var request model.RefreshTokenRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	result, err := h.UseCase.RefreshToken(c.Request().Context(), &request)
+	if err != nil {
+
+		if errors.Is(err, model.ErrInvalidRefreshToken) || errors.Is(err, model.ErrRefreshTokenReused) || errors.Is(err, model.ErrSessionRevoked) {
+			return c.JSON(echo.ErrUnauthorized.Code, model.ResponseError{
+				Code:    echo.ErrUnauthorized.Code,
+				Message: err.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    result,
+		Message: "Token refreshed",
+	})
+}
+
+func (h *Handler) Logout(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+	sessionId, _ := c.Get("sessionId").(string)
+
+	err := h.UseCase.Logout(c.Request().Context(), sessionId, userId)
+	if err != nil {
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    make(map[string]interface{}),
+		Message: "Logged out",
+	})
+}
+
+func (h *Handler) GetSessions(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+	sessionId, _ := c.Get("sessionId").(string)
+
+	result, err := h.UseCase.GetSessionList(c.Request().Context(), userId, sessionId)
+	if err != nil {
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    result,
+		Message: "Ok",
+	})
+}
+
+func (h *Handler) RevokeSession(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	err := h.UseCase.RevokeSession(c.Request().Context(), c.Param("sessionId"), userId)
+	if err != nil {
+
+		if errors.Is(err, model.ErrSessionNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrSessionNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    make(map[string]interface{}),
+		Message: "Session revoked",
+	})
+}
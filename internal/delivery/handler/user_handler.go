@@ -2,8 +2,11 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
 	"github.com/Dzikuri/openidea-segokuning/internal/model"
 	"github.com/labstack/echo/v4"
 )
@@ -19,6 +22,8 @@ func (h *Handler) UserRegister(c echo.Context) error {
 		})
 	}
 
+	request.CredentialValue = helper.NormalizeCredential(request.CredentialType, request.CredentialValue)
+
 	err = request.Validate()
 	if err != nil {
 		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
@@ -28,6 +33,9 @@ func (h *Handler) UserRegister(c echo.Context) error {
 		})
 	}
 
+	request.UserAgent = c.Request().UserAgent()
+	request.IpAddress = c.RealIP()
+
 	result, err := h.UseCase.UserRegister(c.Request().Context(), &request)
 	if err != nil {
 
@@ -72,6 +80,12 @@ func (h *Handler) UserLogin(c echo.Context) error {
 		})
 	}
 
+	// NOTE An identifier logs in with whichever credential it looks like
+	if request.Identifier != "" {
+		request.CredentialType, request.CredentialValue, _ = helper.DetectCredential(request.Identifier)
+	}
+	request.CredentialValue = helper.NormalizeCredential(request.CredentialType, request.CredentialValue)
+
 	err = request.Validate()
 	if err != nil {
 		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
@@ -81,10 +95,23 @@ func (h *Handler) UserLogin(c echo.Context) error {
 		})
 	}
 
+	request.UserAgent = c.Request().UserAgent()
+	request.IpAddress = c.RealIP()
+
 	result, err := h.UseCase.UserLogin(c.Request().Context(), &request)
 
 	if err != nil {
 
+		var locked *model.LoginLockedError
+		if errors.As(err, &locked) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+				Code:    http.StatusTooManyRequests,
+				Message: locked.Error(),
+				Error:   err,
+			})
+		}
+
 		if errors.Is(err, model.ErrPasswordNotMatch) {
 			return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
 				Code:    echo.ErrBadRequest.Code,
@@ -128,6 +155,8 @@ func (h *Handler) UserLinkEmail(c echo.Context) error {
 		})
 	}
 
+	request.Email = helper.NormalizeEmail(request.Email)
+
 	err = request.Validate()
 	if err != nil {
 		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
@@ -142,7 +171,7 @@ func (h *Handler) UserLinkEmail(c echo.Context) error {
 		request.Id = usr.Id
 	}
 
-	_, err = h.UseCase.UserLinkEmail(c.Request().Context(), &request)
+	result, err := h.UseCase.UserLinkEmail(c.Request().Context(), &request)
 	if err != nil {
 
 		if errors.Is(err, model.ErrLinkEmailExists) {
@@ -153,6 +182,14 @@ func (h *Handler) UserLinkEmail(c echo.Context) error {
 			})
 		}
 
+		if errors.Is(err, model.ErrVerificationResendTooSoon) {
+			return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+				Code:    http.StatusTooManyRequests,
+				Message: model.ErrVerificationResendTooSoon.Error(),
+				Error:   err,
+			})
+		}
+
 		if errors.Is(err, model.ErrUserAlreadyExists) {
 			return c.JSON(echo.ErrConflict.Code, model.ResponseError{
 				Code:    echo.ErrConflict.Code,
@@ -179,8 +216,8 @@ func (h *Handler) UserLinkEmail(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, model.Response[any]{
 		Code:    http.StatusOK,
-		Data:    map[string]interface{}{},
-		Message: "Success",
+		Data:    result,
+		Message: "Verification code sent",
 	})
 }
 
@@ -195,6 +232,8 @@ func (h *Handler) UserLinkPhone(c echo.Context) error {
 		})
 	}
 
+	request.Phone = helper.NormalizePhone(request.Phone)
+
 	err = request.Validate()
 	if err != nil {
 		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
@@ -209,7 +248,7 @@ func (h *Handler) UserLinkPhone(c echo.Context) error {
 		request.Id = usr.Id
 	}
 
-	_, err = h.UseCase.UserLinkPhone(c.Request().Context(), &request)
+	result, err := h.UseCase.UserLinkPhone(c.Request().Context(), &request)
 	if err != nil {
 
 		if errors.Is(err, model.ErrLinkEmailExists) {
@@ -220,6 +259,14 @@ func (h *Handler) UserLinkPhone(c echo.Context) error {
 			})
 		}
 
+		if errors.Is(err, model.ErrVerificationResendTooSoon) {
+			return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+				Code:    http.StatusTooManyRequests,
+				Message: model.ErrVerificationResendTooSoon.Error(),
+				Error:   err,
+			})
+		}
+
 		if errors.Is(err, model.ErrUserAlreadyExists) {
 			return c.JSON(echo.ErrConflict.Code, model.ResponseError{
 				Code:    echo.ErrConflict.Code,
@@ -246,8 +293,8 @@ func (h *Handler) UserLinkPhone(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, model.Response[any]{
 		Code:    http.StatusOK,
-		Data:    map[string]interface{}{},
-		Message: "Success",
+		Data:    result,
+		Message: "Verification code sent",
 	})
 }
 
@@ -317,3 +364,127 @@ func (h *Handler) UserUpdateAccount(c echo.Context) error {
 		Message: "Success",
 	})
 }
+
+func (h *Handler) GetMe(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	return h.userProfile(c, userId, userId)
+}
+
+func (h *Handler) GetUserProfile(c echo.Context) error {
+
+	var userId string
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		userId = usr.Id.String()
+	}
+
+	return h.userProfile(c, c.Param("userId"), userId)
+}
+
+// userProfile responds with the profile of id as seen by viewerId.
+func (h *Handler) userProfile(c echo.Context, id string, viewerId string) error {
+
+	result, err := h.UseCase.UserProfile(c.Request().Context(), id, viewerId)
+	if err != nil {
+
+		if errors.Is(err, model.ErrUserNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrUserNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    result,
+		Message: "Ok",
+	})
+}
+
+func (h *Handler) UserLinkConfirm(c echo.Context) error {
+	var request model.UserLinkConfirmRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	err = request.Validate()
+	if err != nil {
+		return c.JSON(model.ErrResBadRequest.Code, model.ResponseError{
+			Code:    model.ErrResBadRequest.Code,
+			Message: model.ErrResBadRequest.Message,
+			Error:   err,
+		})
+	}
+
+	usr, ok := c.Get("userId").(*model.UserResponse)
+	if ok {
+		request.Id = usr.Id
+	}
+
+	err = h.UseCase.UserLinkConfirm(c.Request().Context(), &request)
+	if err != nil {
+
+		if errors.Is(err, model.ErrVerificationNotFound) {
+			return c.JSON(echo.ErrNotFound.Code, model.ResponseError{
+				Code:    echo.ErrNotFound.Code,
+				Message: model.ErrVerificationNotFound.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrVerificationExpired) || errors.Is(err, model.ErrVerificationCodeInvalid) {
+			return c.JSON(echo.ErrBadRequest.Code, model.ResponseError{
+				Code:    echo.ErrBadRequest.Code,
+				Message: err.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrVerificationAttemptsExceeded) {
+			return c.JSON(http.StatusTooManyRequests, model.ResponseError{
+				Code:    http.StatusTooManyRequests,
+				Message: model.ErrVerificationAttemptsExceeded.Error(),
+				Error:   err,
+			})
+		}
+
+		if errors.Is(err, model.ErrUserAlreadyExists) {
+			return c.JSON(echo.ErrConflict.Code, model.ResponseError{
+				Code:    echo.ErrConflict.Code,
+				Message: model.ErrUserAlreadyExists.Error(),
+				Error:   err,
+			})
+		}
+
+		return c.JSON(echo.ErrInternalServerError.Code, model.ResponseError{
+			Code:    echo.ErrInternalServerError.Code,
+			Message: err.Error(),
+			Error:   err,
+		})
+	}
+
+	return c.JSON(http.StatusOK, model.Response[any]{
+		Code:    http.StatusOK,
+		Data:    map[string]interface{}{},
+		Message: "Success",
+	})
+}
@@ -44,7 +44,7 @@ func (m *middleware) Authentication(isThrowError bool) func(next echo.HandlerFun
 
 			if token != "" {
 				claims := &helper.JwtCustomClaims{}
-				err := helper.VerifyJwt(token, claims, helper.JwtSecret())
+				err := helper.VerifyJwt(token, claims)
 				if err != nil {
 					return c.JSON(http.StatusUnauthorized, model.ResponseError{
 						Code:    http.StatusUnauthorized,
@@ -53,6 +53,28 @@ func (m *middleware) Authentication(isThrowError bool) func(next echo.HandlerFun
 					})
 				}
 
+				// NOTE Tokens of a revoked (logged out) session are rejected before they expire
+				active, err := m.UseCase.IsSessionActive(c.Request().Context(), claims.SessionId, claims.Id)
+				if err != nil {
+					return c.JSON(http.StatusInternalServerError, model.ResponseError{
+						Code:    http.StatusInternalServerError,
+						Message: err.Error(),
+					})
+				}
+
+				if !active {
+					return c.JSON(http.StatusUnauthorized, model.ResponseError{
+						Code:    http.StatusUnauthorized,
+						Message: model.ErrSessionRevoked.Error(),
+					})
+				}
+
+				// NOTE Last seen is informational, a failed write does not fail the request
+				err = m.UseCase.TouchSession(c.Request().Context(), claims.SessionId)
+				if err != nil {
+					m.Logger.Warn().Err(err).Str("sessionId", claims.SessionId).Msg("touch session")
+				}
+
 				usr, code, err := m.UseCase.GetUserByID(c.Request().Context(), claims.Id)
 				if err != nil {
 					return c.JSON(code, model.ResponseError{
@@ -61,6 +83,7 @@ func (m *middleware) Authentication(isThrowError bool) func(next echo.HandlerFun
 					})
 				}
 				c.Set("userId", usr)
+				c.Set("sessionId", claims.SessionId)
 			}
 
 			return next(c)
@@ -39,18 +39,38 @@ func (c *RoutesConfig) Setup() {
 func (c *RoutesConfig) SetupRouteAuth() {
 	c.Echo.POST("/v1/user/register", c.Handler.UserRegister)
 	c.Echo.POST("/v1/user/login", c.Handler.UserLogin)
+	c.Echo.POST("/v1/user/token/refresh", c.Handler.RefreshToken)
+	c.Echo.POST("/v1/user/logout", c.Handler.Logout, c.Middleware.Authentication(true))
+	c.Echo.POST("/v1/user/password/forgot", c.Handler.UserForgotPassword)
+	c.Echo.POST("/v1/user/password/reset", c.Handler.UserResetPassword)
 }
 
 func (c *RoutesConfig) SetupRouteUser() {
 	c.Echo.POST("/v1/user/link", c.Handler.UserLinkEmail, c.Middleware.Authentication(true))
 	c.Echo.POST("/v1/user/link/phone", c.Handler.UserLinkPhone, c.Middleware.Authentication(true))
+	c.Echo.POST("/v1/user/link/confirm", c.Handler.UserLinkConfirm, c.Middleware.Authentication(true))
+	c.Echo.PUT("/v1/user/credential", c.Handler.UserReplaceCredential, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/user/credential", c.Handler.UserRemoveCredential, c.Middleware.Authentication(true))
 	c.Echo.PATCH("/v1/user", c.Handler.UserUpdateAccount, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/user", c.Handler.DeleteAccount, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/user/export", c.Handler.ExportAccount, c.Middleware.Authentication(true))
+	c.Echo.PATCH("/v1/user/password", c.Handler.UserChangePassword, c.Middleware.Authentication(true))
+	c.Echo.POST("/v1/user/block", c.Handler.BlockUser, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/user/block", c.Handler.UnblockUser, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/user/sessions", c.Handler.GetSessions, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/user/sessions/:sessionId", c.Handler.RevokeSession, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/user/me", c.Handler.GetMe, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/user/:userId", c.Handler.GetUserProfile, c.Middleware.Authentication(true))
 }
 
 func (c *RoutesConfig) SetupRouteFriends() {
 	c.Echo.POST("/v1/friend", c.Handler.CreateFriend, c.Middleware.Authentication(true))
 	c.Echo.GET("/v1/friend", c.Handler.GetFriends, c.Middleware.Authentication(true))
 	c.Echo.DELETE("/v1/friend", c.Handler.DeleteFriend, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/friend/request", c.Handler.GetFriendRequests, c.Middleware.Authentication(true))
+	c.Echo.POST("/v1/friend/request/:requestId/accept", c.Handler.AcceptFriendRequest, c.Middleware.Authentication(true))
+	c.Echo.POST("/v1/friend/request/:requestId/reject", c.Handler.RejectFriendRequest, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/friend/request/:requestId", c.Handler.CancelFriendRequest, c.Middleware.Authentication(true))
 }
 
 func (c *RoutesConfig) SetupRouteImageUpload() {
@@ -62,4 +82,15 @@ func (c *RoutesConfig) SetupRoutePost() {
 	c.Echo.POST("/v1/post", c.Handler.CreatePost, c.Middleware.Authentication(true))
 	c.Echo.GET("/v1/post", c.Handler.GetPosts, c.Middleware.Authentication(true))
 	c.Echo.POST("/v1/post/comment", c.Handler.CreatePostComment, c.Middleware.Authentication(true))
+	c.Echo.PATCH("/v1/post/comment/:commentId", c.Handler.UpdatePostComment, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/post/comment/:commentId", c.Handler.DeletePostComment, c.Middleware.Authentication(true))
+	c.Echo.PUT("/v1/post/comment/:commentId/reaction", c.Handler.ReactToComment, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/post/comment/:commentId/reaction", c.Handler.RemoveCommentReaction, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/post/:postId", c.Handler.GetPost, c.Middleware.Authentication(true))
+	c.Echo.PATCH("/v1/post/:postId", c.Handler.UpdatePost, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/post/:postId", c.Handler.DeletePost, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/post/:postId/comments", c.Handler.GetPostComments, c.Middleware.Authentication(true))
+	c.Echo.GET("/v1/post/:postId/history", c.Handler.GetPostHistory, c.Middleware.Authentication(true))
+	c.Echo.PUT("/v1/post/:postId/reaction", c.Handler.ReactToPost, c.Middleware.Authentication(true))
+	c.Echo.DELETE("/v1/post/:postId/reaction", c.Handler.RemovePostReaction, c.Middleware.Authentication(true))
 }
@@ -0,0 +1,67 @@
+package helper
+
+import (
+	"strings"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+// NormalizeEmail trims and case-folds an email address.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone strips formatting from a phone number and turns a 00 international prefix into +,
+// so "+62 812-3456-7890" and "0062 (812) 34567890" both become "+6281234567890".
+// It does not guess a country: a national number such as "0812-3456-7890" stays "081234567890",
+// which model.PhoneRegexp rejects, so clients must send the number in international (E.164) form.
+func NormalizePhone(phone string) string {
+	phone = strings.TrimSpace(phone)
+
+	var b strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			// NOTE Unknown characters are kept so validation rejects the value
+			b.WriteRune(r)
+		}
+	}
+
+	normalized := b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + strings.TrimPrefix(normalized, "00")
+	}
+
+	return normalized
+}
+
+// NormalizeCredential normalizes value according to credentialType.
+func NormalizeCredential(credentialType model.UserCredentialType, value string) string {
+	switch credentialType {
+	case model.Email:
+		return NormalizeEmail(value)
+	case model.Phone:
+		return NormalizePhone(value)
+	}
+
+	return value
+}
+
+// DetectCredential tells whether identifier is an email or a phone number, using the
+// same rules as model.UserAuthRequest, and returns it normalized.
+func DetectCredential(identifier string) (model.UserCredentialType, string, bool) {
+	if email := NormalizeEmail(identifier); model.EmailRegexp.MatchString(email) {
+		return model.Email, email, true
+	}
+
+	if phone := NormalizePhone(identifier); model.PhoneRegexp.MatchString(phone) {
+		return model.Phone, phone, true
+	}
+
+	return "", identifier, false
+}
@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+// EncodeCursor builds an opaque keyset cursor from the created_at and id of the last row of a page.
+func EncodeCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", model.ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || !IsValidUUID(parts[1]) {
+		return time.Time{}, "", model.ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", model.ErrInvalidCursor
+	}
+
+	return createdAt, parts[1], nil
+}
@@ -1,58 +1,204 @@
 package helper
 
 import (
+	"crypto/ed25519"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Dzikuri/openidea-segokuning/internal/model"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
 )
 
-func JwtSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "secret"
+// JwtConfig holds how access tokens are signed and verified, see LoadJwtConfig.
+type JwtConfig struct {
+	Method     jwt.SigningMethod
+	KeyId      string
+	SigningKey any
+	// VerifyKeys maps a key id to its verification key, previous keys are kept here during a rotation
+	VerifyKeys map[string]any
+	ExpireAt   time.Duration
+	Issuer     string
+	Audience   string
+}
+
+var jwtConfig *JwtConfig
+
+// SetJwtConfig replaces the configuration used by JwtGenerateToken and VerifyJwt.
+func SetJwtConfig(config *JwtConfig) {
+	jwtConfig = config
+}
+
+// LoadJwtConfig reads the JWT configuration from the environment:
+//
+//	JWT_SIGNING_METHOD    HS256 (default), RS256 or EdDSA
+//	JWT_SECRET            shared secret, required for HS256
+//	JWT_PRIVATE_KEY_FILE  PEM private key, required for RS256 and EdDSA
+//	JWT_KEY_ID            kid header of issued tokens, defaults to "default"
+//	JWT_PUBLIC_KEY_FILES  extra verification keys as kid=path pairs separated by commas
+//	JWT_EXPIRE_AT         access token lifetime in hours, defaults to 2
+//	JWT_ISSUER            iss claim, validated when set
+//	JWT_AUDIENCE          aud claim, validated when set
+func LoadJwtConfig() (*JwtConfig, error) {
+	config := &JwtConfig{
+		KeyId:      os.Getenv("JWT_KEY_ID"),
+		VerifyKeys: map[string]any{},
+		Issuer:     os.Getenv("JWT_ISSUER"),
+		Audience:   os.Getenv("JWT_AUDIENCE"),
+	}
+
+	if config.KeyId == "" {
+		config.KeyId = "default"
+	}
+
+	expire, err := JwtExpireAt()
+	if err != nil {
+		return nil, err
+	}
+	config.ExpireAt = time.Duration(expire) * time.Hour
+
+	method := os.Getenv("JWT_SIGNING_METHOD")
+	if method == "" {
+		method = jwt.SigningMethodHS256.Alg()
+	}
+
+	switch method {
+	case jwt.SigningMethodHS256.Alg():
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("JWT_SECRET is not set")
+		}
+
+		config.Method = jwt.SigningMethodHS256
+		config.SigningKey = []byte(secret)
+		config.VerifyKeys[config.KeyId] = []byte(secret)
+
+		return config, nil
+	case jwt.SigningMethodRS256.Alg():
+		config.Method = jwt.SigningMethodRS256
+	case jwt.SigningMethodEdDSA.Alg():
+		config.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported JWT_SIGNING_METHOD %q", method)
+	}
+
+	privateKeyFile := os.Getenv("JWT_PRIVATE_KEY_FILE")
+	if privateKeyFile == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_FILE is not set")
+	}
+
+	pem, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "read JWT_PRIVATE_KEY_FILE")
+	}
+
+	if config.Method == jwt.SigningMethodRS256 {
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse JWT_PRIVATE_KEY_FILE")
+		}
+		config.SigningKey = privateKey
+		config.VerifyKeys[config.KeyId] = privateKey.Public()
+	} else {
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse JWT_PRIVATE_KEY_FILE")
+		}
+		edPrivateKey, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("JWT_PRIVATE_KEY_FILE is not an Ed25519 key")
+		}
+		config.SigningKey = edPrivateKey
+		config.VerifyKeys[config.KeyId] = edPrivateKey.Public()
+	}
+
+	// NOTE Keys of a previous rotation keep verifying the tokens they signed until those expire
+	for _, pair := range strings.Split(os.Getenv("JWT_PUBLIC_KEY_FILES"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		kid, path, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid JWT_PUBLIC_KEY_FILES entry %q, want kid=path", pair)
+		}
+
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read public key %q", kid)
+		}
+
+		var publicKey any
+		if config.Method == jwt.SigningMethodRS256 {
+			publicKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		} else {
+			publicKey, err = jwt.ParseEdPublicKeyFromPEM(pem)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse public key %q", kid)
+		}
+
+		config.VerifyKeys[kid] = publicKey
 	}
 
-	return secret
+	return config, nil
 }
 
-func JwtExpireAt() int {
+// JwtExpireAt returns the access token lifetime in hours from JWT_EXPIRE_AT.
+func JwtExpireAt() (int, error) {
 	expireAt := os.Getenv("JWT_EXPIRE_AT")
 	if expireAt == "" {
 		expireAt = "2"
 	}
 
-	expire, _ := strconv.Atoi(expireAt)
+	expire, err := strconv.Atoi(expireAt)
+	if err != nil || expire <= 0 {
+		return 0, fmt.Errorf("JWT_EXPIRE_AT must be a positive number of hours, got %q", expireAt)
+	}
 
-	return expire
+	return expire, nil
 }
 
 type JwtCustomClaims struct {
-	Name string `json:"name"`
-	Id   string `json:"userId"`
+	Name      string `json:"name"`
+	Id        string `json:"userId"`
+	SessionId string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func JwtGenerateToken(request *model.UserResponse) (string, error) {
+func JwtGenerateToken(request *model.User, sessionId string) (string, error) {
+
+	if jwtConfig == nil {
+		return "", errors.New("jwt is not configured")
+	}
+
+	now := time.Now()
 
 	// Generate Claims object
 	jwtClaims := JwtCustomClaims{
-		Name: request.Name,
-		Id:   request.Id.String(),
+		Name:      request.Name,
+		Id:        request.Id.String(),
+		SessionId: sessionId,
 		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 2)),
+			Issuer:    jwtConfig.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(jwtConfig.ExpireAt)),
 		},
 	}
 
-	// Create token with claims
-	// token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
+	if jwtConfig.Audience != "" {
+		jwtClaims.Audience = jwt.ClaimStrings{jwtConfig.Audience}
+	}
+
+	// Create token with claims, kid tells VerifyJwt which key signed it
+	token := jwt.NewWithClaims(jwtConfig.Method, jwtClaims)
+	token.Header["kid"] = jwtConfig.KeyId
 
 	// Generate encoded token and send it as response.
-	t, err := token.SignedString([]byte(JwtSecret()))
+	t, err := token.SignedString(jwtConfig.SigningKey)
 	if err != nil {
 		return "", err
 	}
@@ -60,10 +206,33 @@ func JwtGenerateToken(request *model.UserResponse) (string, error) {
 	return t, err
 }
 
-func VerifyJwt(tokenString string, claims jwt.Claims, secret string) error {
+func VerifyJwt(tokenString string, claims jwt.Claims) error {
+
+	if jwtConfig == nil {
+		return model.ErrUnauthorize
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{jwtConfig.Method.Alg()}), jwt.WithExpirationRequired()}
+	if jwtConfig.Issuer != "" {
+		options = append(options, jwt.WithIssuer(jwtConfig.Issuer))
+	}
+	if jwtConfig.Audience != "" {
+		options = append(options, jwt.WithAudience(jwtConfig.Audience))
+	}
+
 	tkn, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
-		return []byte(JwtSecret()), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			kid = jwtConfig.KeyId
+		}
+
+		key, ok := jwtConfig.VerifyKeys[kid]
+		if !ok {
+			return nil, model.ErrUnauthorize
+		}
+
+		return key, nil
+	}, options...)
 	if err != nil {
 		if err == jwt.ErrSignatureInvalid {
 			return model.ErrUnauthorize
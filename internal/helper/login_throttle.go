@@ -0,0 +1,58 @@
+package helper
+
+import "time"
+
+// LoginThrottle decides how long a credential or ip address waits after failed logins.
+type LoginThrottle struct {
+	// FreeAttempts failures are allowed without any delay
+	FreeAttempts int
+	// BaseDelay doubles with every failure past FreeAttempts, up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutAfter failures lock the login for LockoutDuration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	// ResetAfter without failures starts counting from zero again
+	ResetAfter time.Duration
+}
+
+// CredentialLoginThrottle applies to a single email or phone number.
+var CredentialLoginThrottle = LoginThrottle{
+	FreeAttempts:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        5 * time.Minute,
+	LockoutAfter:    10,
+	LockoutDuration: 15 * time.Minute,
+	ResetAfter:      time.Hour,
+}
+
+// IpLoginThrottle applies to a client ip address, which may be shared by many users behind a NAT.
+var IpLoginThrottle = LoginThrottle{
+	FreeAttempts:    20,
+	BaseDelay:       time.Second,
+	MaxDelay:        5 * time.Minute,
+	LockoutAfter:    100,
+	LockoutDuration: 15 * time.Minute,
+	ResetAfter:      time.Hour,
+}
+
+// Delay returns how long to wait after the given number of consecutive failures.
+func (t LoginThrottle) Delay(failures int) time.Duration {
+	if failures >= t.LockoutAfter {
+		return t.LockoutDuration
+	}
+
+	if failures <= t.FreeAttempts {
+		return 0
+	}
+
+	delay := t.BaseDelay
+	for i := t.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.MaxDelay {
+			return t.MaxDelay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,64 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RefreshTokenExpireAt returns the refresh token lifetime, REFRESH_TOKEN_EXPIRE_AT is in hours.
+func RefreshTokenExpireAt() time.Duration {
+	expireAt := os.Getenv("REFRESH_TOKEN_EXPIRE_AT")
+	if expireAt == "" {
+		expireAt = "720"
+	}
+
+	expire, err := strconv.Atoi(expireAt)
+	if err != nil || expire <= 0 {
+		expire = 720
+	}
+
+	return time.Duration(expire) * time.Hour
+}
+
+// GenerateRefreshToken returns a random opaque token and the hash to store in place of it.
+func GenerateRefreshToken() (string, string, error) {
+	return GenerateSecretToken()
+}
+
+// GenerateSecretToken returns a random url safe token and its HashToken.
+func GenerateSecretToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex encoded sha256 of token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateVerificationCode returns a random numeric code of the given length.
+func GenerateVerificationCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+
+	return string(code), nil
+}
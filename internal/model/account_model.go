@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/itgelo/ozzo-validation/v4"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AccountDeletionGracePeriod is how long a deleted account is kept before it is purged.
+const AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+type UserDeleteRequest struct {
+	Id       uuid.UUID `json:"-"`
+	Password string    `json:"password"`
+}
+
+type UserDeleteResponse struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"`
+}
+
+func (p UserDeleteRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Password, validation.Required.Error(ErrResRequiredField.Message)),
+	)
+}
+
+// UserExportResponse is the archive of everything a user wrote or linked to their account.
+type UserExportResponse struct {
+	Profile    UserExportProfile   `json:"profile"`
+	Friends    []UserExportFriend  `json:"friends"`
+	Posts      []UserExportPost    `json:"posts"`
+	Comments   []UserExportComment `json:"comments"`
+	ExportedAt time.Time           `json:"exportedAt"`
+}
+
+type UserExportProfile struct {
+	Id          uuid.UUID `json:"id"`
+	Phone       string    `json:"phone"`
+	Email       string    `json:"email"`
+	Name        string    `json:"name"`
+	ImageUrl    string    `json:"imageUrl"`
+	FriendCount int       `json:"friendCount"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type UserExportFriend struct {
+	UserId       uuid.UUID `json:"userId"`
+	Name         string    `json:"name"`
+	FriendsSince time.Time `json:"friendsSince"`
+}
+
+type UserExportPost struct {
+	Id         uuid.UUID      `json:"id"`
+	Content    string         `json:"content"`
+	Tags       pq.StringArray `json:"tags"`
+	Visibility PostVisibility `json:"visibility"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+type UserExportComment struct {
+	Id              uuid.UUID `json:"id"`
+	PostId          uuid.UUID `json:"postId"`
+	ParentCommentId string    `json:"parentCommentId"`
+	Comment         string    `json:"comment"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/itgelo/ozzo-validation/v4"
+)
+
+type BlockType string
+
+var BlockTypes []interface{} = []interface{}{BlockTypeBlock, BlockTypeMute}
+
+const (
+	BlockTypeBlock BlockType = "block"
+	BlockTypeMute  BlockType = "mute"
+)
+
+type BlockRequest struct {
+	UserId        string    `json:"userId"`
+	BlockedUserId string    `json:"blockedUserId,omitempty"`
+	Type          BlockType `json:"type"`
+}
+
+type BlockResponse struct {
+	Id            string    `json:"id"`
+	UserId        string    `json:"userId"`
+	BlockedUserId string    `json:"blockedUserId"`
+	Type          BlockType `json:"type"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func (p BlockRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.UserId, validation.Required),
+		validation.Field(&p.Type, validation.In(BlockTypes...)),
+	)
+}
@@ -27,6 +27,38 @@ var (
 	ErrFriendAlreadyExists = errors.New("You already be friend")
 	ErrInvalidUserId       = errors.New("Invalid UserId")
 	ErrNotFriend           = errors.New("Not Friend")
+	ErrInvalidCursor       = errors.New("Invalid cursor")
+
+	ErrFriendRequestPending    = errors.New("Friend request already sent")
+	ErrFriendRequestNotFound   = errors.New("Friend request not found")
+	ErrFriendRequestNotPending = errors.New("Friend request is no longer pending")
+
+	ErrCommentNotFound      = errors.New("Comment not found")
+	ErrCommentDepthExceeded = errors.New("Comment replies are nested too deep")
+
+	ErrReactionNotFound = errors.New("Reaction not found")
+
+	ErrInvalidRefreshToken = errors.New("Invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("Refresh token already used, session revoked")
+	ErrSessionRevoked      = errors.New("Session revoked")
+	ErrSessionNotFound     = errors.New("Session not found")
+	ErrLoginLocked         = errors.New("Too many failed login attempts")
+
+	ErrVerificationNotFound         = errors.New("No pending verification")
+	ErrVerificationExpired          = errors.New("Verification code expired")
+	ErrVerificationCodeInvalid      = errors.New("Invalid verification code")
+	ErrVerificationAttemptsExceeded = errors.New("Too many invalid verification codes, try again later")
+	ErrVerificationResendTooSoon    = errors.New("A verification code was just sent, wait before requesting another one")
+
+	ErrInvalidPasswordReset = errors.New("Invalid or expired password reset token")
+	ErrPasswordResetTooSoon = errors.New("A password reset was just sent, wait before requesting another one")
+
+	ErrLastCredential      = errors.New("At least one of email or phone must remain")
+	ErrCredentialNotLinked = errors.New("Credential is not linked")
+	ErrCredentialUnchanged = errors.New("Credential is already linked to this account")
+
+	ErrBlocked    = errors.New("User is blocked")
+	ErrNotBlocked = errors.New("User is not blocked")
 
 	ErrFileSizeNotValid  = errors.New("file size is not valid")
 	ErrExtensionNotValid = errors.New("file extension is not valid")
@@ -30,6 +30,46 @@ type FriendResponse struct {
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+type FriendRequestStatus string
+
+const (
+	FriendRequestPending   FriendRequestStatus = "pending"
+	FriendRequestAccepted  FriendRequestStatus = "accepted"
+	FriendRequestRejected  FriendRequestStatus = "rejected"
+	FriendRequestCancelled FriendRequestStatus = "cancelled"
+)
+
+type FriendRequestType string
+
+var FriendRequestTypes []interface{} = []interface{}{FriendRequestIncoming, FriendRequestOutgoing}
+
+const (
+	FriendRequestIncoming FriendRequestType = "incoming"
+	FriendRequestOutgoing FriendRequestType = "outgoing"
+)
+
+type FriendRequestResponse struct {
+	Id         string              `json:"id"`
+	SenderId   string              `json:"senderId"`
+	ReceiverId string              `json:"receiverId"`
+	Status     FriendRequestStatus `json:"status"`
+	User       *FriendResponse     `json:"user,omitempty"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	UpdatedAt  time.Time           `json:"updatedAt"`
+}
+
+type GetFriendRequestListRequest struct {
+	UserId string            `json:"userId"`
+	Type   FriendRequestType `form:"type" query:"type" json:"type"`
+	Limit  int               `form:"limit" query:"limit" json:"limit"`
+	Offset int               `form:"offset" query:"offset" json:"offset"`
+}
+
+type FriendRequestActionRequest struct {
+	Id     string `json:"id"`
+	UserId string `json:"userId"`
+}
+
 func (p FriendRequest) Validate() error {
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.UserId, validation.Required),
@@ -47,3 +87,11 @@ func (p GetFriendListRequest) Validate() error {
 		// validation.Field(&p.OnlyFriend, validation.Bool),
 	)
 }
+
+func (p GetFriendRequestListRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Type, validation.In(FriendRequestTypes...)),
+		validation.Field(&p.Limit, validation.Min(0), validation.Max(100)),
+		validation.Field(&p.Offset, validation.Min(0)),
+	)
+}
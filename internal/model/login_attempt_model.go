@@ -0,0 +1,27 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginAttempt tracks the failed logins of one credential or ip address.
+type LoginAttempt struct {
+	Key          string
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  time.Time
+}
+
+// LoginLockedError is returned while a credential or ip address has to wait before the next login.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked.Error(), e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/itgelo/ozzo-validation/v4"
+	uuid "github.com/satori/go.uuid"
+)
+
+const PasswordResetTTL = 30 * time.Minute
+
+// PasswordResetResendInterval is the minimum time between two reset tokens sent for the same account.
+const PasswordResetResendInterval = time.Minute
+
+type PasswordReset struct {
+	Id        string
+	UserId    string
+	ExpiresAt time.Time
+}
+
+type UserChangePasswordRequest struct {
+	Id              uuid.UUID `json:"-"`
+	SessionId       string    `json:"-"`
+	CurrentPassword string    `json:"currentPassword"`
+	NewPassword     string    `json:"newPassword"`
+}
+
+type UserForgotPasswordRequest struct {
+	CredentialType  UserCredentialType `json:"credentialType"`
+	CredentialValue string             `json:"credentialValue"`
+}
+
+type UserResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (p UserChangePasswordRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.CurrentPassword, validation.Required.Error(ErrResRequiredField.Message)),
+		validation.Field(&p.NewPassword, validation.Required.Error(ErrResRequiredField.Message), validation.Length(5, 15)),
+	)
+}
+
+func (p UserForgotPasswordRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.CredentialType, validation.Required.Error(ErrResRequiredField.Message), validation.In(UserCredentialTypes...)),
+		validation.Field(&p.CredentialValue, validation.Required, validation.By(func(value interface{}) error {
+			switch p.CredentialType {
+			case Email:
+				if err := validation.Validate(value, validation.Match(EmailRegexp)); err != nil {
+					return errors.New("Invalid email format")
+				}
+			case Phone:
+				if err := validation.Validate(value, validation.Match(PhoneRegexp)); err != nil {
+					return errors.New("Invalid phone number format")
+				}
+			}
+			return nil
+		})),
+	)
+}
+
+func (p UserResetPasswordRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Token, validation.Required.Error(ErrResRequiredField.Message)),
+		validation.Field(&p.NewPassword, validation.Required.Error(ErrResRequiredField.Message), validation.Length(5, 15)),
+	)
+}
@@ -7,46 +7,118 @@ import (
 	"github.com/lib/pq"
 )
 
+type PostVisibility string
+
+var PostVisibilities []interface{} = []interface{}{PostVisibilityPublic, PostVisibilityFriends, PostVisibilityOnlyMe}
+
+const (
+	PostVisibilityPublic  PostVisibility = "public"
+	PostVisibilityFriends PostVisibility = "friends"
+	PostVisibilityOnlyMe  PostVisibility = "only_me"
+)
+
 type CreatePostRequest struct {
+	UserId     string         `json:"userId"`
+	Content    string         `json:"postInHtml"`
+	Tags       []string       `json:"tags,omitempty"`
+	Visibility PostVisibility `json:"visibility,omitempty"`
+}
+
+type UpdatePostRequest struct {
+	Id      string   `json:"-"`
 	UserId  string   `json:"userId"`
 	Content string   `json:"postInHtml"`
 	Tags    []string `json:"tags,omitempty"`
 }
 
+type PostHistoryResponse struct {
+	Id         string         `json:"id"`
+	PostId     string         `json:"postId"`
+	Content    string         `json:"postInHtml"`
+	Tags       pq.StringArray `json:"tags"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	ReplacedAt time.Time      `json:"replacedAt"`
+}
+
+// MaxCommentDepth is the deepest level a reply can be nested at, top level comments are depth 0.
+const MaxCommentDepth = 3
+
 type CreatePostCommentRequest struct {
-	PostId  string `json:"postId"`
-	UserId  string `json:"userId"`
-	Comment string `json:"comment"`
+	PostId          string `json:"postId"`
+	UserId          string `json:"userId"`
+	Comment         string `json:"comment"`
+	ParentCommentId string `json:"parentCommentId,omitempty"`
+	Depth           int    `json:"-"`
 }
 
 type PostResponse struct {
-	Id        string         `json:"id"`
-	UserId    string         `json:"userId"`
-	Content   string         `json:"postInHtml"`
-	Tags      pq.StringArray `json:"tags"`
-	CreatedAt time.Time      `json:"createdAt"`
-	UpdatedAt time.Time      `json:"updatedAt"`
+	Id         string         `json:"id"`
+	UserId     string         `json:"userId"`
+	Content    string         `json:"postInHtml"`
+	Tags       pq.StringArray `json:"tags"`
+	Visibility PostVisibility `json:"visibility"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+// CommentRemovedMessage replaces the text of a soft deleted comment.
+const CommentRemovedMessage = "comment removed"
+
+type UpdatePostCommentRequest struct {
+	Id      string `json:"-"`
+	UserId  string `json:"userId"`
+	Comment string `json:"comment"`
 }
 
 type PostCommentResponse struct {
-	Id        string    `json:"id"`
-	PostId    string    `json:"postId"`
-	UserId    string    `json:"userId"`
-	Comment   string    `json:"comment"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	Id              string    `json:"id"`
+	PostId          string    `json:"postId"`
+	UserId          string    `json:"userId"`
+	ParentCommentId string    `json:"parentCommentId,omitempty"`
+	Depth           int       `json:"depth"`
+	Comment         string    `json:"comment"`
+	IsDeleted       bool      `json:"isDeleted"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
 }
 
 type PostCommentUserResponse struct {
 	PostCommentResponse
-	Creator FriendResponse `json:"creator"`
+	Creator    FriendResponse            `json:"creator"`
+	Reactions  map[ReactionType]int      `json:"reactions"`
+	MyReaction ReactionType              `json:"myReaction,omitempty"`
+	Replies    []PostCommentUserResponse `json:"replies"`
+	// IsPlaceholder marks a parent that was not loaded with its replies, only its ids and depth are set.
+	IsPlaceholder bool `json:"isPlaceholder,omitempty"`
 }
 
+// FeedCommentLimit is how many of the latest comments are embedded in each feed entry.
+const FeedCommentLimit = 5
+
+// CommentPageLimit is the size of a comment page when none is requested, the post detail embeds the first page.
+const CommentPageLimit = 10
+
 type PostListResponse struct {
-	PostId   string                    `json:"postId"`
-	Post     PostResponse              `json:"post"`
-	Comments []PostCommentUserResponse `json:"comments"`
-	Creator  FriendResponse            `json:"creator"`
+	PostId       string                    `json:"postId"`
+	Post         PostResponse              `json:"post"`
+	Comments     []PostCommentUserResponse `json:"comments"`
+	CommentCount int                       `json:"commentCount"`
+	Reactions    map[ReactionType]int      `json:"reactions"`
+	MyReaction   ReactionType              `json:"myReaction,omitempty"`
+	Creator      FriendResponse            `json:"creator"`
+}
+
+// PostDetailResponse is a feed entry whose comments are the first page of the post's comments.
+type PostDetailResponse struct {
+	PostListResponse
+	CommentMeta MetaDataResponse `json:"commentMeta"`
+}
+
+type PostCommentListRequest struct {
+	PostId string `json:"postId"`
+	UserId string `json:"userId"`
+	Limit  int    `form:"limit" query:"limit" json:"limit"`
+	Cursor string `form:"cursor" query:"cursor" json:"cursor"`
 }
 
 type PostListRequest struct {
@@ -57,12 +129,26 @@ type PostListRequest struct {
 	// OrderBy    string `form:"orderBy" query:"orderBy" json:"orderBy"`
 	Search    string   `form:"search" query:"search" json:"search"`
 	SearchTag []string `form:"searchTag" query:"searchTag" json:"searchTag"`
+	// Cursor switches the listing to keyset pagination, see helper.EncodeCursor
+	Cursor string `form:"cursor" query:"cursor" json:"cursor"`
+	// Explore lists posts of every user instead of only the caller and their friends
+	Explore bool `form:"explore" query:"explore" json:"explore"`
+	// PostId narrows the listing to a single post, used by the post detail
+	PostId string `json:"-"`
 }
 
 func (r CreatePostRequest) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Content, validation.Required, validation.Length(2, 500)),
 		validation.Field(&r.Tags, validation.Required, validation.Each(validation.Required), validation.Length(1, 20)),
+		validation.Field(&r.Visibility, validation.In(PostVisibilities...)),
+	)
+}
+
+func (r UpdatePostRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Content, validation.Required.When(len(r.Tags) == 0), validation.Length(2, 500)),
+		validation.Field(&r.Tags, validation.Each(validation.Required), validation.Length(1, 20)),
 	)
 }
 
@@ -73,9 +159,82 @@ func (r CreatePostCommentRequest) Validate() error {
 	)
 }
 
+func (r UpdatePostCommentRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Comment, validation.Required, validation.Length(2, 500)),
+	)
+}
+
+func (p PostCommentListRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Limit, validation.Min(0), validation.Max(100)),
+	)
+}
+
 func (p PostListRequest) Validate() error {
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.Limit, validation.Min(0), validation.Max(100), validation.When(p.Limit != 0, validation.Required)),
-		validation.Field(&p.Offset, validation.Min(0), validation.When(p.Offset != 0, validation.Required)),
+		validation.Field(&p.Offset, validation.Min(0), validation.When(p.Offset != 0, validation.Required), validation.When(p.Cursor != "", validation.Empty)),
 	)
 }
+
+// CommentNode is the place of a comment in its thread.
+type CommentNode struct {
+	Id              string
+	ParentCommentId string
+	UserId          string
+	Depth           int
+}
+
+// ReparentComments returns the comments whose parent or depth changes once the comments written by
+// removedUserIds are deleted. Each remaining comment moves under its nearest remaining ancestor, or to
+// the top level when there is none, so replies of other users are not deleted along with their parent.
+// comments has to hold every comment of the posts involved.
+func ReparentComments(removedUserIds []string, comments []CommentNode) []CommentNode {
+	removed := make(map[string]bool, len(removedUserIds))
+	for _, id := range removedUserIds {
+		removed[id] = true
+	}
+
+	byId := make(map[string]CommentNode, len(comments))
+	for _, comment := range comments {
+		byId[comment.Id] = comment
+	}
+
+	placed := make(map[string]CommentNode, len(comments))
+	var place func(comment CommentNode) CommentNode
+	place = func(comment CommentNode) CommentNode {
+		if result, ok := placed[comment.Id]; ok {
+			return result
+		}
+
+		result := comment
+		result.ParentCommentId = ""
+		result.Depth = 0
+
+		parent, ok := byId[comment.ParentCommentId]
+		for ok && removed[parent.UserId] {
+			parent, ok = byId[parent.ParentCommentId]
+		}
+		if ok {
+			result.ParentCommentId = parent.Id
+			result.Depth = place(parent).Depth + 1
+		}
+
+		placed[comment.Id] = result
+		return result
+	}
+
+	changed := make([]CommentNode, 0)
+	for _, comment := range comments {
+		if removed[comment.UserId] {
+			continue
+		}
+
+		if result := place(comment); result != comment {
+			changed = append(changed, result)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/itgelo/ozzo-validation/v4"
+)
+
+type ReactionType string
+
+var ReactionTypes []interface{} = []interface{}{ReactionLike, ReactionLove, ReactionHaha, ReactionWow, ReactionSad, ReactionAngry}
+
+const (
+	ReactionLike  ReactionType = "like"
+	ReactionLove  ReactionType = "love"
+	ReactionHaha  ReactionType = "haha"
+	ReactionWow   ReactionType = "wow"
+	ReactionSad   ReactionType = "sad"
+	ReactionAngry ReactionType = "angry"
+)
+
+// ReactionRequest targets a post when PostId is set, a comment otherwise.
+type ReactionRequest struct {
+	UserId    string       `json:"userId"`
+	PostId    string       `json:"-"`
+	CommentId string       `json:"-"`
+	Type      ReactionType `json:"type"`
+}
+
+type ReactionResponse struct {
+	Id        string       `json:"id"`
+	UserId    string       `json:"userId"`
+	PostId    string       `json:"postId,omitempty"`
+	CommentId string       `json:"commentId,omitempty"`
+	Type      ReactionType `json:"type"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+func (r ReactionRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Type, validation.Required, validation.In(ReactionTypes...)),
+	)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	validation "github.com/itgelo/ozzo-validation/v4"
+)
+
+type Session struct {
+	Id         string
+	UserId     string
+	UserAgent  string
+	IpAddress  string
+	LastSeenAt sql.NullTime
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// SessionResponse is an active login of the caller, Current marks the one making the request.
+type SessionResponse struct {
+	Id         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IpAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Current    bool      `json:"current"`
+}
+
+// RefreshToken is a refresh_tokens row joined with its session.
+type RefreshToken struct {
+	Id             string
+	SessionId      string
+	UserId         string
+	UsedAt         sql.NullTime
+	ExpiresAt      time.Time
+	SessionRevoked bool
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (r RefreshTokenRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.RefreshToken, validation.Required.Error(ErrResRequiredField.Message)),
+	)
+}
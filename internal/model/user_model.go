@@ -10,28 +10,110 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+// User is the users row, it carries the password hash and must never be sent to clients.
+type User struct {
+	Id        uuid.UUID
+	Phone     sql.NullString
+	Email     sql.NullString
+	Name      string
+	ImageUrl  string
+	Password  string `json:"-"`
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+// UserResponse is the public view of a User.
 type UserResponse struct {
-	Id        uuid.UUID      `json:"id,omitempty"`
-	Phone     sql.NullString `json:"phone,omitempty"`
-	Email     sql.NullString `json:"email,omitempty"`
-	Name      string         `json:"name,omitempty"`
-	ImageUrl  string         `json:"imageUrl,omitempty"`
-	Password  string         `json:"password"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	CreatedAt time.Time      `json:"createdAt"`
+	Id        uuid.UUID `json:"id,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	ImageUrl  string    `json:"imageUrl,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewUserResponse(user *User) *UserResponse {
+	return &UserResponse{
+		Id:        user.Id,
+		Phone:     user.Phone.String,
+		Email:     user.Email.String,
+		Name:      user.Name,
+		ImageUrl:  user.ImageUrl,
+		UpdatedAt: user.UpdatedAt,
+		CreatedAt: user.CreatedAt,
+	}
 }
 
 type UserAuthResponse struct {
-	Phone       string `json:"phone"`
-	Email       string `json:"email"`
-	Name        string `json:"name,omitempty"`
-	AccessToken string `json:"accessToken,omitempty"`
+	Phone        string `json:"phone"`
+	Email        string `json:"email"`
+	Name         string `json:"name,omitempty"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type FriendshipStatus string
+
+const (
+	FriendshipSelf            FriendshipStatus = "self"
+	FriendshipFriends         FriendshipStatus = "friends"
+	FriendshipRequestSent     FriendshipStatus = "request_sent"
+	FriendshipRequestReceived FriendshipStatus = "request_received"
+	FriendshipBlocked         FriendshipStatus = "blocked"
+	FriendshipNone            FriendshipStatus = "none"
+)
+
+// FriendshipRelation is how a viewer relates to the user whose profile they look at.
+type FriendshipRelation struct {
+	Self            bool
+	Blocked         bool
+	Friends         bool
+	RequestSent     bool
+	RequestReceived bool
+}
+
+// Status returns the single status shown on the profile, the first relation that holds in field order wins.
+func (f FriendshipRelation) Status() FriendshipStatus {
+	switch {
+	case f.Self:
+		return FriendshipSelf
+	case f.Blocked:
+		return FriendshipBlocked
+	case f.Friends:
+		return FriendshipFriends
+	case f.RequestSent:
+		return FriendshipRequestSent
+	case f.RequestReceived:
+		return FriendshipRequestReceived
+	default:
+		return FriendshipNone
+	}
+}
+
+// UserProfileResponse is a user as seen by the caller, FriendshipStatus is relative to the caller.
+type UserProfileResponse struct {
+	Id                string           `json:"id"`
+	Name              string           `json:"name"`
+	ImageUrl          string           `json:"imageUrl"`
+	FriendCount       int              `json:"friendCount"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	MutualFriendCount int              `json:"mutualFriendCount"`
+	FriendshipStatus  FriendshipStatus `json:"friendshipStatus"`
+	// Relation is what the repository loads, FriendshipStatus is derived from it
+	Relation FriendshipRelation `json:"-"`
 }
 
 type UserCredentialType string
 
 var UserCredentialTypes []interface{} = []interface{}{Email, Phone}
 
+// EmailRegexp and PhoneRegexp validate credential values, phone numbers are in E.164 form.
+var (
+	EmailRegexp = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	PhoneRegexp = regexp.MustCompile(`^\+[0-9]{7,13}$`)
+)
+
 const (
 	Email UserCredentialType = "email"
 	Phone UserCredentialType = "phone"
@@ -42,12 +124,18 @@ type UserAuthRequest struct {
 	CredentialValue string             `json:"credentialValue"`
 	Name            string             `json:"name"`
 	Password        string             `json:"password"`
+	UserAgent       string             `json:"-"`
+	IpAddress       string             `json:"-"`
 }
 
 type UserLoginRequest struct {
+	// Identifier is an email or phone number, it replaces CredentialType and CredentialValue when set
+	Identifier      string             `json:"identifier"`
 	CredentialType  UserCredentialType `json:"credentialType"`
 	CredentialValue string             `json:"credentialValue"`
 	Password        string             `json:"password"`
+	UserAgent       string             `json:"-"`
+	IpAddress       string             `json:"-"`
 }
 
 type UserLinkEmailRequest struct {
@@ -66,6 +154,47 @@ type UserUpdateAccount struct {
 	ImageUrl string    `json:"imageUrl"`
 }
 
+// UserReplaceCredentialRequest replaces (or sets) the email or phone of a user, the new value has to be verified.
+type UserReplaceCredentialRequest struct {
+	Id              uuid.UUID          `json:"-"`
+	CredentialType  UserCredentialType `json:"credentialType"`
+	CredentialValue string             `json:"credentialValue"`
+	Password        string             `json:"password"`
+}
+
+type UserRemoveCredentialRequest struct {
+	Id             uuid.UUID          `json:"-"`
+	CredentialType UserCredentialType `json:"credentialType"`
+	Password       string             `json:"password"`
+}
+
+func (p UserReplaceCredentialRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.CredentialType, validation.Required.Error(ErrResRequiredField.Message), validation.In(UserCredentialTypes...)),
+		validation.Field(&p.CredentialValue, validation.Required, validation.By(func(value interface{}) error {
+			switch p.CredentialType {
+			case Email:
+				if err := validation.Validate(value, validation.Match(EmailRegexp)); err != nil {
+					return errors.New("Invalid email format")
+				}
+			case Phone:
+				if err := validation.Validate(value, validation.Match(PhoneRegexp)); err != nil {
+					return errors.New("Invalid phone number format")
+				}
+			}
+			return nil
+		})),
+		validation.Field(&p.Password, validation.Required.Error(ErrResRequiredField.Message)),
+	)
+}
+
+func (p UserRemoveCredentialRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.CredentialType, validation.Required.Error(ErrResRequiredField.Message), validation.In(UserCredentialTypes...)),
+		validation.Field(&p.Password, validation.Required.Error(ErrResRequiredField.Message)),
+	)
+}
+
 func (p UserUpdateAccount) Validate() error {
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.Name, validation.Required, validation.Length(5, 50)),
@@ -75,13 +204,13 @@ func (p UserUpdateAccount) Validate() error {
 
 func (p UserLinkPhoneRequest) Validate() error {
 	return validation.ValidateStruct(&p,
-		validation.Field(&p.Phone, validation.Required.Error(ErrResRequiredField.Message), validation.Match(regexp.MustCompile(`^\+[0-9]{7,13}$`))),
+		validation.Field(&p.Phone, validation.Required.Error(ErrResRequiredField.Message), validation.Match(PhoneRegexp)),
 	)
 }
 
 func (p UserLinkEmailRequest) Validate() error {
 	return validation.ValidateStruct(&p,
-		validation.Field(&p.Email, validation.Required.Error(ErrResRequiredField.Message), validation.Match(regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`))),
+		validation.Field(&p.Email, validation.Required.Error(ErrResRequiredField.Message), validation.Match(EmailRegexp)),
 	)
 }
 
@@ -94,11 +223,11 @@ func (p UserAuthRequest) Validate() error {
 		validation.Field(&p.CredentialValue, validation.Required, validation.By(func(value interface{}) error {
 			switch p.CredentialType {
 			case Email:
-				if err := validation.Validate(value, validation.Match(regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`))); err != nil {
+				if err := validation.Validate(value, validation.Match(EmailRegexp)); err != nil {
 					return errors.New("Invalid email format")
 				}
 			case Phone:
-				if err := validation.Validate(value, validation.Match(regexp.MustCompile(`^\+[0-9]{7,13}$`))); err != nil {
+				if err := validation.Validate(value, validation.Match(PhoneRegexp)); err != nil {
 					return errors.New("Invalid phone number format")
 				}
 			}
@@ -120,11 +249,11 @@ func (p UserLoginRequest) Validate() error {
 		validation.Field(&p.CredentialValue, validation.Required, validation.By(func(value interface{}) error {
 			switch p.CredentialType {
 			case Email:
-				if err := validation.Validate(value, validation.Match(regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`))); err != nil {
+				if err := validation.Validate(value, validation.Match(EmailRegexp)); err != nil {
 					return errors.New("Invalid email format")
 				}
 			case Phone:
-				if err := validation.Validate(value, validation.Match(regexp.MustCompile(`^\+[0-9]{7,13}$`))); err != nil {
+				if err := validation.Validate(value, validation.Match(PhoneRegexp)); err != nil {
 					return errors.New("Invalid phone number format")
 				}
 			}
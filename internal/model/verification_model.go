@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	validation "github.com/itgelo/ozzo-validation/v4"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	VerificationCodeTTL         = 15 * time.Minute
+	VerificationCodeMaxAttempts = 5
+	// VerificationResendInterval is the minimum time between two codes sent for the same credential type
+	VerificationResendInterval = time.Minute
+	// VerificationAttemptWindow is how long invalid attempts are kept across re-sent codes
+	VerificationAttemptWindow = time.Hour
+)
+
+// CredentialVerification is an email or phone number linked to a user but not yet confirmed.
+type CredentialVerification struct {
+	Id              string
+	UserId          string
+	CredentialType  UserCredentialType
+	CredentialValue string
+	CodeHash        string
+	Attempts        int
+	ExpiresAt       time.Time
+	SentAt          time.Time
+	CreatedAt       time.Time
+}
+
+// ReissueVerification returns the attempts and window start of a code sent now while previous is pending.
+// Codes re-sent within VerificationAttemptWindow keep the invalid attempts of the previous ones.
+func ReissueVerification(previous *CredentialVerification, now time.Time) (attempts int, windowStart time.Time, err error) {
+	if previous == nil {
+		return 0, now, nil
+	}
+
+	if now.Sub(previous.SentAt) < VerificationResendInterval {
+		return 0, time.Time{}, ErrVerificationResendTooSoon
+	}
+
+	if now.Sub(previous.CreatedAt) < VerificationAttemptWindow {
+		return previous.Attempts, previous.CreatedAt, nil
+	}
+
+	return 0, now, nil
+}
+
+type CredentialVerificationResponse struct {
+	CredentialType  UserCredentialType `json:"credentialType"`
+	CredentialValue string             `json:"credentialValue"`
+	ExpiresAt       time.Time          `json:"expiresAt"`
+}
+
+type UserLinkConfirmRequest struct {
+	Id             uuid.UUID          `json:"-"`
+	CredentialType UserCredentialType `json:"credentialType"`
+	Code           string             `json:"code"`
+}
+
+func (p UserLinkConfirmRequest) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.CredentialType, validation.Required.Error(ErrResRequiredField.Message), validation.In(UserCredentialTypes...)),
+		validation.Field(&p.Code, validation.Required.Error(ErrResRequiredField.Message), validation.Length(6, 6)),
+	)
+}
+
+// Notification is a message delivered to an email address or phone number.
+type Notification struct {
+	Channel UserCredentialType
+	To      string
+	Subject string
+	Body    string
+}
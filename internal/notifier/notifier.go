@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/rs/zerolog"
+)
+
+// Notifier delivers a message to an email address or phone number.
+type Notifier interface {
+	Send(ctx context.Context, notification model.Notification) error
+}
+
+// LogNotifier writes notifications to the application log instead of delivering them,
+// a stand-in until an email and sms provider is configured.
+type LogNotifier struct {
+	Logger zerolog.Logger
+}
+
+func NewLogNotifier(logger zerolog.Logger) Notifier {
+	return &LogNotifier{
+		Logger: logger,
+	}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, notification model.Notification) error {
+	n.Logger.Info().
+		Str("channel", string(notification.Channel)).
+		Str("to", notification.To).
+		Str("subject", notification.Subject).
+		Str("body", notification.Body).
+		Msg("notification")
+
+	return nil
+}
+
+// FileNotifier appends notifications as JSON lines to a file, handy to read codes in local testing.
+type FileNotifier struct {
+	mu   sync.Mutex
+	Path string
+}
+
+func NewFileNotifier(path string) Notifier {
+	return &FileNotifier{
+		Path: path,
+	}
+}
+
+func (n *FileNotifier) Send(ctx context.Context, notification model.Notification) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"channel": notification.Channel,
+		"to":      notification.To,
+		"subject": notification.Subject,
+		"body":    notification.Body,
+		"sentAt":  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type AccountRepository struct {
+	DB *sql.DB
+}
+
+type RepositoryAccount interface {
+	DeleteAccount(ctx context.Context, userId string) (time.Time, error)
+	PurgeDeletedAccounts(ctx context.Context, deletedBefore time.Time) (int64, error)
+	ExportAccount(ctx context.Context, userId string) (*model.UserExportResponse, error)
+}
+
+func NewAccountRepository(db *sql.DB) RepositoryAccount {
+	return &AccountRepository{
+		DB: db,
+	}
+}
+
+// DeleteAccount marks userId as deleted, revokes all of its sessions and cancels its pending friend requests,
+// the rows are removed by PurgeDeletedAccounts.
+func (r *AccountRepository) DeleteAccount(ctx context.Context, userId string) (time.Time, error) {
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return time.Time{}, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(context, `UPDATE users SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`, dateTime, userId)
+	if err != nil {
+		return time.Time{}, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return time.Time{}, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		err = model.ErrUserNotFound
+		return time.Time{}, err
+	}
+
+	_, err = tx.ExecContext(context, `UPDATE sessions SET revoked_at = $1, updated_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`, dateTime, userId)
+	if err != nil {
+		return time.Time{}, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	// NOTE Pending requests in both directions are cancelled so none can be accepted during the grace period
+	queryCancel := `UPDATE friend_requests SET status = $1, updated_at = $2 WHERE (sender_id = $3 OR receiver_id = $3) AND status = $4`
+	_, err = tx.ExecContext(context, queryCancel, model.FriendRequestCancelled, dateTime, userId, model.FriendRequestPending)
+	if err != nil {
+		return time.Time{}, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return time.Time{}, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return dateTime, nil
+}
+
+// PurgeDeletedAccounts removes the accounts deleted before deletedBefore and returns how many were removed.
+// The accounts are locked with SKIP LOCKED, so concurrent purges of several instances never handle the same account twice.
+func (r *AccountRepository) PurgeDeletedAccounts(ctx context.Context, deletedBefore time.Time) (int64, error) {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	rows, err := tx.QueryContext(context, `SELECT id FROM users WHERE deleted_at < $1 ORDER BY id FOR UPDATE SKIP LOCKED`, deletedBefore)
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	var purgedIds pq.StringArray
+	for rows.Next() {
+		var id string
+		err = rows.Scan(&id)
+		if err != nil {
+			rows.Close()
+			return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		purgedIds = append(purgedIds, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if len(purgedIds) == 0 {
+		err = tx.Commit()
+		if err != nil {
+			return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		return 0, nil
+	}
+
+	// NOTE The friends rows go away with the cascade, so the counters of the remaining side are fixed up first
+	rows, err = tx.QueryContext(context, `SELECT user_id, follow_user_id FROM friends WHERE follow_user_id = ANY($1::uuid[])`, purgedIds)
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	var friendships [][2]string
+	for rows.Next() {
+		var friendship [2]string
+		err = rows.Scan(&friendship[0], &friendship[1])
+		if err != nil {
+			rows.Close()
+			return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		friendships = append(friendships, friendship)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	decrements := FriendCountDecrements(purgedIds, friendships)
+	if len(decrements) > 0 {
+		var userIds pq.StringArray
+		var totals pq.Int64Array
+		for userId, total := range decrements {
+			userIds = append(userIds, userId)
+			totals = append(totals, int64(total))
+		}
+
+		queryUpdateFollowerCount := `UPDATE users SET total_friend = GREATEST(COALESCE(users.total_friend, 0) - purged.total, 0)
+		FROM unnest($1::uuid[], $2::integer[]) AS purged(user_id, total)
+		WHERE users.id = purged.user_id`
+		_, err = tx.ExecContext(context, queryUpdateFollowerCount, userIds, totals)
+		if err != nil {
+			return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+	}
+
+	// NOTE Comments cascade to their replies, so replies of other users are moved out from under the purged comments first
+	rows, err = tx.QueryContext(context, `SELECT id, COALESCE(parent_comment_id::text, ''), user_id, depth FROM post_comments
+	WHERE post_id IN (SELECT post_id FROM post_comments WHERE user_id = ANY($1::uuid[])) FOR UPDATE`, purgedIds)
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	var comments []model.CommentNode
+	for rows.Next() {
+		var comment model.CommentNode
+		err = rows.Scan(&comment.Id, &comment.ParentCommentId, &comment.UserId, &comment.Depth)
+		if err != nil {
+			rows.Close()
+			return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		comments = append(comments, comment)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	moved := model.ReparentComments(purgedIds, comments)
+	if len(moved) > 0 {
+		var commentIds, parentIds pq.StringArray
+		var depths pq.Int64Array
+		for _, comment := range moved {
+			commentIds = append(commentIds, comment.Id)
+			parentIds = append(parentIds, comment.ParentCommentId)
+			depths = append(depths, int64(comment.Depth))
+		}
+
+		queryReparent := `UPDATE post_comments SET parent_comment_id = NULLIF(moved.parent_id, '')::uuid, depth = moved.depth
+		FROM unnest($1::uuid[], $2::text[], $3::integer[]) AS moved(id, parent_id, depth)
+		WHERE post_comments.id = moved.id`
+		_, err = tx.ExecContext(context, queryReparent, commentIds, parentIds, depths)
+		if err != nil {
+			return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+	}
+
+	result, err := tx.ExecContext(context, `DELETE FROM users WHERE id = ANY($1::uuid[])`, purgedIds)
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return row, nil
+}
+
+// FriendCountDecrements returns how much total_friend drops for each remaining user once purgedIds are removed.
+// friendships holds friends rows as (user_id, follow_user_id) pairs; users being purged themselves are left out.
+func FriendCountDecrements(purgedIds []string, friendships [][2]string) map[string]int {
+	purged := make(map[string]bool, len(purgedIds))
+	for _, id := range purgedIds {
+		purged[id] = true
+	}
+
+	decrements := make(map[string]int)
+	for _, friendship := range friendships {
+		if purged[friendship[1]] && !purged[friendship[0]] {
+			decrements[friendship[0]]++
+		}
+	}
+
+	return decrements
+}
+
+// ExportAccount collects the profile, friends, posts and comments of userId.
+func (r *AccountRepository) ExportAccount(ctx context.Context, userId string) (*model.UserExportResponse, error) {
+
+	export := model.UserExportResponse{
+		Friends:    make([]model.UserExportFriend, 0),
+		Posts:      make([]model.UserExportPost, 0),
+		Comments:   make([]model.UserExportComment, 0),
+		ExportedAt: time.Now(),
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	queryProfile := `SELECT id, COALESCE(phone, ''), COALESCE(email, ''), name, COALESCE(image_url, ''), COALESCE(total_friend, 0), COALESCE(updated_at, created_at), created_at FROM users WHERE id = $1 AND deleted_at IS NULL`
+	err := r.DB.QueryRowContext(context, queryProfile, userId).Scan(&export.Profile.Id, &export.Profile.Phone, &export.Profile.Email, &export.Profile.Name, &export.Profile.ImageUrl, &export.Profile.FriendCount, &export.Profile.UpdatedAt, &export.Profile.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	rows, err := r.DB.QueryContext(context, `SELECT users.id, users.name, friends.created_at FROM friends JOIN users ON users.id = friends.follow_user_id WHERE friends.user_id = $1 ORDER BY friends.created_at ASC`, userId)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var friend model.UserExportFriend
+		err = rows.Scan(&friend.UserId, &friend.Name, &friend.FriendsSince)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		export.Friends = append(export.Friends, friend)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	rows, err = r.DB.QueryContext(context, `SELECT id, content, tags, visibility, COALESCE(updated_at, created_at), created_at FROM posts WHERE user_id = $1 ORDER BY created_at ASC`, userId)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post model.UserExportPost
+		err = rows.Scan(&post.Id, &post.Content, &post.Tags, &post.Visibility, &post.UpdatedAt, &post.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		export.Posts = append(export.Posts, post)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	// NOTE Comments the user deleted are left out of the archive
+	rows, err = r.DB.QueryContext(context, `SELECT id, post_id, COALESCE(parent_comment_id::text, ''), comment, COALESCE(updated_at, created_at), created_at FROM post_comments WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at ASC`, userId)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment model.UserExportComment
+		err = rows.Scan(&comment.Id, &comment.PostId, &comment.ParentCommentId, &comment.Comment, &comment.UpdatedAt, &comment.CreatedAt)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+		export.Comments = append(export.Comments, comment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &export, nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/pkg/errors"
+)
+
+type BlockRepository struct {
+	DB *sql.DB
+}
+
+type RepositoryBlock interface {
+	Block(ctx context.Context, request model.BlockRequest) (*model.BlockResponse, error)
+	Unblock(ctx context.Context, userID string, blockedUserID string) error
+	IsBlocked(ctx context.Context, userID string, otherUserID string) (bool, error)
+}
+
+func NewBlockRepository(db *sql.DB) RepositoryBlock {
+	return &BlockRepository{
+		DB: db,
+	}
+}
+
+// hiddenFromViewer returns a condition that is true when content written by
+// authorColumn must not be shown to viewer: the author blocked the viewer, or
+// the viewer blocked or muted the author, or the author deleted their account.
+func hiddenFromViewer(authorColumn string, viewer string) string {
+	return fmt.Sprintf(`(EXISTS (SELECT 1 FROM user_blocks WHERE (user_blocks.user_id = %[1]s AND user_blocks.blocked_user_id = %[2]s AND user_blocks.type = '%[3]s') OR (user_blocks.user_id = %[2]s AND user_blocks.blocked_user_id = %[1]s)) OR EXISTS (SELECT 1 FROM users AS authors WHERE authors.id = %[1]s AND authors.deleted_at IS NOT NULL))`, authorColumn, viewer, model.BlockTypeBlock)
+}
+
+// Block stores a block or mute of request.BlockedUserId. A block also ends the friendship and cancels
+// pending friend requests in both directions, all in one transaction.
+func (r *BlockRepository) Block(ctx context.Context, request model.BlockRequest) (*model.BlockResponse, error) {
+
+	var block model.BlockResponse
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// NOTE Blocking a muted user (or muting a blocked one) replaces the previous type
+	query := `INSERT INTO user_blocks (user_id, blocked_user_id, type, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)
+	ON CONFLICT (user_id, blocked_user_id) DO UPDATE SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at
+	RETURNING id, user_id, blocked_user_id, type, created_at, updated_at`
+
+	err = tx.QueryRowContext(context, query, request.UserId, request.BlockedUserId, request.Type, dateTime).Scan(&block.Id, &block.UserId, &block.BlockedUserId, &block.Type, &block.CreatedAt, &block.UpdatedAt)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	// NOTE A mute only hides content, friendship stays as is
+	if request.Type == model.BlockTypeBlock {
+		var result sql.Result
+		result, err = tx.ExecContext(context, `DELETE FROM friends WHERE (user_id = $1 AND follow_user_id = $2) OR (user_id = $2 AND follow_user_id = $1)`, request.UserId, request.BlockedUserId)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+
+		var row int64
+		row, err = result.RowsAffected()
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+
+		if row > 0 {
+			_, err = tx.ExecContext(context, `UPDATE users SET total_friend = total_friend - 1 WHERE id IN ($1, $2)`, request.UserId, request.BlockedUserId)
+			if err != nil {
+				return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+			}
+		}
+
+		// NOTE Drop pending friend requests in both directions
+		queryCancel := `UPDATE friend_requests SET status = $1, updated_at = $2
+		WHERE status = $3 AND ((sender_id = $4 AND receiver_id = $5) OR (sender_id = $5 AND receiver_id = $4))`
+		_, err = tx.ExecContext(context, queryCancel, model.FriendRequestCancelled, dateTime, model.FriendRequestPending, request.UserId, request.BlockedUserId)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &block, nil
+}
+
+func (r *BlockRepository) Unblock(ctx context.Context, userID string, blockedUserID string) error {
+
+	if !helper.IsValidUUID(blockedUserID) {
+		return model.ErrNotBlocked
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(context, `DELETE FROM user_blocks WHERE user_id = $1 AND blocked_user_id = $2`, userID, blockedUserID)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		return model.ErrNotBlocked
+	}
+
+	return nil
+}
+
+// IsBlocked reports whether either user blocked the other. Mutes are ignored.
+func (r *BlockRepository) IsBlocked(ctx context.Context, userID string, otherUserID string) (bool, error) {
+
+	var blocked bool
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT EXISTS (SELECT 1 FROM user_blocks WHERE type = $3 AND ((user_id = $1 AND blocked_user_id = $2) OR (user_id = $2 AND blocked_user_id = $1)))`
+
+	err := r.DB.QueryRowContext(context, query, userID, otherUserID, model.BlockTypeBlock).Scan(&blocked)
+	if err != nil {
+		return false, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return blocked, nil
+}
@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
 	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pkg/errors"
 )
 
@@ -17,7 +19,12 @@ type FriendRepository struct {
 }
 
 type RepositoryFriend interface {
-	AddFriend(ctx context.Context, request model.FriendRequest) (*model.FriendResponse, error)
+	CreateFriendRequest(ctx context.Context, request model.FriendRequest) (*model.FriendRequestResponse, error)
+	FindFriendRequestById(ctx context.Context, id string) (*model.FriendRequestResponse, error)
+	FindPendingFriendRequest(ctx context.Context, senderID string, receiverID string) (*model.FriendRequestResponse, error)
+	FindAllFriendRequest(ctx context.Context, request model.GetFriendRequestListRequest) ([]model.FriendRequestResponse, model.MetaDataResponse, error)
+	UpdateFriendRequestStatus(ctx context.Context, id string, status model.FriendRequestStatus) (*model.FriendRequestResponse, error)
+	AcceptFriendRequest(ctx context.Context, id string) (*model.FriendRequestResponse, error)
 	CheckAlreadyFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, int, error)
 	RemoveFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error)
 	FindAllFriend(ctx context.Context, request model.GetFriendListRequest) ([]model.FriendResponse, model.MetaDataResponse, error)
@@ -29,7 +36,162 @@ func NewFriendRepository(db *sql.DB) RepositoryFriend {
 	}
 }
 
-func (f *FriendRepository) AddFriend(ctx context.Context, request model.FriendRequest) (*model.FriendResponse, error) {
+// selfOrFriend returns a condition that is true when userColumn is the viewer or one of the viewer's friends.
+func selfOrFriend(userColumn string, viewer string) string {
+	return fmt.Sprintf(`(%[1]s = %[2]s OR EXISTS (SELECT 1 FROM friends WHERE friends.user_id = %[2]s AND friends.follow_user_id = %[1]s))`, userColumn, viewer)
+}
+
+func (f *FriendRepository) CreateFriendRequest(ctx context.Context, request model.FriendRequest) (*model.FriendRequestResponse, error) {
+
+	var friendRequest model.FriendRequestResponse
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	queryCreate := `INSERT INTO friend_requests (sender_id, receiver_id, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $4) RETURNING id, sender_id, receiver_id, status, created_at, updated_at`
+
+	err := f.DB.QueryRowContext(context, queryCreate, request.UserId, request.FriendId, model.FriendRequestPending, time.Now()).Scan(&friendRequest.Id, &friendRequest.SenderId, &friendRequest.ReceiverId, &friendRequest.Status, &friendRequest.CreatedAt, &friendRequest.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, model.ErrFriendRequestPending
+		}
+		return nil, err
+	}
+
+	return &friendRequest, nil
+}
+
+func (f *FriendRepository) FindFriendRequestById(ctx context.Context, id string) (*model.FriendRequestResponse, error) {
+
+	var friendRequest model.FriendRequestResponse
+
+	if !helper.IsValidUUID(id) {
+		return nil, model.ErrFriendRequestNotFound
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	querySelect := `SELECT id, sender_id, receiver_id, status, created_at, updated_at FROM friend_requests WHERE id = $1`
+
+	err := f.DB.QueryRowContext(context, querySelect, id).Scan(&friendRequest.Id, &friendRequest.SenderId, &friendRequest.ReceiverId, &friendRequest.Status, &friendRequest.CreatedAt, &friendRequest.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrFriendRequestNotFound
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &friendRequest, nil
+}
+
+func (f *FriendRepository) FindPendingFriendRequest(ctx context.Context, senderID string, receiverID string) (*model.FriendRequestResponse, error) {
+
+	var friendRequest model.FriendRequestResponse
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	querySelect := `SELECT id, sender_id, receiver_id, status, created_at, updated_at FROM friend_requests WHERE sender_id = $1 AND receiver_id = $2 AND status = $3`
+
+	err := f.DB.QueryRowContext(context, querySelect, senderID, receiverID, model.FriendRequestPending).Scan(&friendRequest.Id, &friendRequest.SenderId, &friendRequest.ReceiverId, &friendRequest.Status, &friendRequest.CreatedAt, &friendRequest.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrFriendRequestNotFound
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &friendRequest, nil
+}
+
+func (f *FriendRepository) FindAllFriendRequest(ctx context.Context, request model.GetFriendRequestListRequest) ([]model.FriendRequestResponse, model.MetaDataResponse, error) {
+
+	friendRequests := make([]model.FriendRequestResponse, 0)
+
+	// NOTE Incoming requests show the sender, outgoing requests show the receiver
+	queryJoin := " INNER JOIN users ON users.id = friend_requests.sender_id "
+	query := NewQueryBuilder()
+	if request.Type == model.FriendRequestOutgoing {
+		queryJoin = " INNER JOIN users ON users.id = friend_requests.receiver_id "
+		query.WhereEqual("friend_requests.sender_id", request.UserId)
+	} else {
+		query.WhereEqual("friend_requests.receiver_id", request.UserId)
+	}
+	query.WhereEqual("friend_requests.status", model.FriendRequestPending)
+
+	queryFrom := fmt.Sprintf("FROM friend_requests %s %s", queryJoin, query.Condition())
+	countArgs := query.Args()
+
+	queryGet := fmt.Sprintf("SELECT friend_requests.id, friend_requests.sender_id, friend_requests.receiver_id, friend_requests.status, friend_requests.created_at, friend_requests.updated_at, users.id, users.name, users.image_url, users.total_friend, users.created_at, (SELECT count(*) %s) AS total_count %s ORDER BY friend_requests.created_at DESC LIMIT %s OFFSET %s", queryFrom, queryFrom, query.Arg(request.Limit), query.Arg(request.Offset))
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	rows, err := f.DB.QueryContext(context, queryGet, query.Args()...)
+	if err != nil {
+		return nil, model.MetaDataResponse{}, err
+	}
+	defer rows.Close()
+
+	total := 0
+	for rows.Next() {
+		var friendRequest model.FriendRequestResponse
+		var user model.FriendResponse
+
+		err = rows.Scan(&friendRequest.Id, &friendRequest.SenderId, &friendRequest.ReceiverId, &friendRequest.Status, &friendRequest.CreatedAt, &friendRequest.UpdatedAt, &user.UserId, &user.Name, &user.ImageUrl, &user.FriendCount, &user.CreatedAt, &total)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+
+		friendRequest.User = &user
+		friendRequests = append(friendRequests, friendRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, model.MetaDataResponse{}, err
+	}
+
+	// NOTE A page past the end has no row to carry the total, count it separately
+	if len(friendRequests) == 0 && request.Offset > 0 {
+		err = f.DB.QueryRowContext(context, "SELECT count(*) "+queryFrom, countArgs...).Scan(&total)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+	}
+
+	return friendRequests, model.MetaDataResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}, nil
+}
+
+func (f *FriendRepository) UpdateFriendRequestStatus(ctx context.Context, id string, status model.FriendRequestStatus) (*model.FriendRequestResponse, error) {
+
+	var friendRequest model.FriendRequestResponse
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// NOTE Only a pending request can move to another status
+	queryUpdate := `UPDATE friend_requests SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4 RETURNING id, sender_id, receiver_id, status, created_at, updated_at`
+
+	err := f.DB.QueryRowContext(context, queryUpdate, status, time.Now(), id, model.FriendRequestPending).Scan(&friendRequest.Id, &friendRequest.SenderId, &friendRequest.ReceiverId, &friendRequest.Status, &friendRequest.CreatedAt, &friendRequest.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrFriendRequestNotPending
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &friendRequest, nil
+}
+
+func (f *FriendRepository) AcceptFriendRequest(ctx context.Context, id string) (*model.FriendRequestResponse, error) {
+
+	var friendRequest model.FriendRequestResponse
 
 	dateTime := time.Now()
 	dateCreate := dateTime.Format(time.RFC3339)
@@ -49,16 +211,26 @@ func (f *FriendRepository) AddFriend(ctx context.Context, request model.FriendRe
 		}
 	}()
 
+	// Mark the request accepted, only while it is still pending
+	queryAccept := `UPDATE friend_requests SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4 RETURNING id, sender_id, receiver_id, status, created_at, updated_at`
+	err = tx.QueryRowContext(context, queryAccept, model.FriendRequestAccepted, dateTime, id, model.FriendRequestPending).Scan(&friendRequest.Id, &friendRequest.SenderId, &friendRequest.ReceiverId, &friendRequest.Status, &friendRequest.CreatedAt, &friendRequest.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrFriendRequestNotPending
+		}
+		return nil, err
+	}
+
 	// Insert into friends table
 	queryCreate := `INSERT INTO friends (user_id, follow_user_id, created_at, updated_at) VALUES ($1, $2, $3, $4), ($2, $1, $3, $4) RETURNING id`
-	_, err = tx.ExecContext(context, queryCreate, request.UserId, request.FriendId, dateCreate, dateCreate)
+	_, err = tx.ExecContext(context, queryCreate, friendRequest.SenderId, friendRequest.ReceiverId, dateCreate, dateCreate)
 	if err != nil {
 		return nil, err
 	}
 
 	// Update total_friend count in users table
 	queryUpdateFollowerCount := `UPDATE users SET total_friend = total_friend + 1 WHERE id IN ($1, $2)`
-	_, err = tx.ExecContext(context, queryUpdateFollowerCount, request.UserId, request.FriendId)
+	_, err = tx.ExecContext(context, queryUpdateFollowerCount, friendRequest.SenderId, friendRequest.ReceiverId)
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +241,7 @@ func (f *FriendRepository) AddFriend(ctx context.Context, request model.FriendRe
 		return nil, err
 	}
 
-	// If everything succeeded, return nil error
-	return nil, nil
+	return &friendRequest, nil
 }
 
 func (f *FriendRepository) RemoveFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error) {
@@ -122,7 +293,7 @@ func (f *FriendRepository) CheckAlreadyFriend(ctx context.Context, userID string
 	defer cancel()
 
 	var id string
-	row := f.DB.QueryRowContext(context, "SELECT id FROM users WHERE id = $1", friendID)
+	row := f.DB.QueryRowContext(context, "SELECT id FROM users WHERE id = $1 AND deleted_at IS NULL", friendID)
 
 	err := row.Scan(&id)
 
@@ -155,52 +326,56 @@ func (f *FriendRepository) FindAllFriend(ctx context.Context, request model.GetF
 
 	friends := make([]model.FriendResponse, 0)
 
-	queryCondition := fmt.Sprintf(" WHERE users.id <> '%s' ", request.UserId)
+	query := NewQueryBuilder()
+	query.WhereNotEqual("users.id", request.UserId)
+	query.Where("users.deleted_at IS NULL")
+
 	queryJoin := ""
 	if request.OnlyFriend == true {
-		queryCondition += fmt.Sprintf(" AND friends.user_id = '%s' ", request.UserId)
+		query.WhereEqual("friends.user_id", request.UserId)
 
 		// NOTE Using Join
 		queryJoin += " INNER JOIN friends ON users.id = friends.follow_user_id "
 	}
 
 	if request.Search != "" {
-		queryCondition += fmt.Sprintf(" AND users.name LIKE '%%%s%%' ", request.Search)
+		query.WhereLike("users.name", request.Search)
 	}
 
 	orderBy := "DESC"
-	if request.OrderBy != "" {
-		orderBy = request.OrderBy
+	if strings.ToLower(request.OrderBy) == "asc" {
+		orderBy = "ASC"
 	}
 
 	sortBy := "created_at"
-	if request.SortBy != "" {
-		if request.SortBy == "friendCount" {
-			sortBy = "total_friend"
-		} else {
-			sortBy = "created_at"
-		}
+	if request.SortBy == "friendCount" {
+		sortBy = "total_friend"
 	}
 
-	querySortBy := fmt.Sprintf(" ORDER BY %s %s ", sortBy, orderBy)
+	querySortBy := fmt.Sprintf(" ORDER BY users.%s %s ", sortBy, orderBy)
+
+	// NOTE Total is counted with the same filters in the same round trip
+	queryFrom := fmt.Sprintf("FROM users %s %s", queryJoin, query.Condition())
+	countArgs := query.Args()
 
 	// NOTE Using Join
-	queryGet := fmt.Sprintf("SELECT users.id, users.name, users.image_url, users.total_friend, users.created_at FROM users %s %s %s LIMIT $1 OFFSET $2", queryJoin, queryCondition, querySortBy)
+	queryGet := fmt.Sprintf("SELECT users.id, users.name, users.image_url, users.total_friend, users.created_at, (SELECT count(*) %s) AS total_count %s %s LIMIT %s OFFSET %s", queryFrom, queryFrom, querySortBy, query.Arg(request.Limit), query.Arg(request.Offset))
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	rows, err := f.DB.QueryContext(context, queryGet, request.Limit, request.Offset)
+	rows, err := f.DB.QueryContext(context, queryGet, query.Args()...)
 	if err != nil {
 		return nil, model.MetaDataResponse{}, err
 	}
+	defer rows.Close()
 
-	totalRows := 0
+	total := 0
 	for rows.Next() {
 		var friend model.FriendResponse
 
 		var createdAt time.Time
 
-		err = rows.Scan(&friend.UserId, &friend.Name, &friend.ImageUrl, &friend.FriendCount, &createdAt)
+		err = rows.Scan(&friend.UserId, &friend.Name, &friend.ImageUrl, &friend.FriendCount, &createdAt, &total)
 		if err != nil {
 			return nil, model.MetaDataResponse{}, err
 		}
@@ -208,14 +383,23 @@ func (f *FriendRepository) FindAllFriend(ctx context.Context, request model.GetF
 		// friend.CreatedAt = createdAt.Format("")
 		friend.CreatedAt, _ = time.Parse(time.RFC3339, friend.CreatedAt.String())
 		friends = append(friends, friend)
+	}
 
-		totalRows++
+	if err = rows.Err(); err != nil {
+		return nil, model.MetaDataResponse{}, err
+	}
+
+	// NOTE A page past the end has no row to carry the total, count it separately
+	if len(friends) == 0 && request.Offset > 0 {
+		err = f.DB.QueryRowContext(context, "SELECT count(*) "+queryFrom, countArgs...).Scan(&total)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
 	}
-	defer rows.Close()
 
 	return friends, model.MetaDataResponse{
 		Limit:  request.Limit,
 		Offset: request.Offset,
-		Total:  totalRows,
+		Total:  total,
 	}, nil
 }
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/pkg/errors"
+)
+
+// RepositoryLoginAttempt stores failed logins, see NewLoginAttemptRepository and NewLoginAttemptMemoryRepository.
+type RepositoryLoginAttempt interface {
+	// FindLoginAttempt returns an empty attempt when key has no failures
+	FindLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error)
+	// RecordLoginFailure counts a failure, restarting from one when the previous failure is older than resetAfter
+	RecordLoginFailure(ctx context.Context, key string, now time.Time, resetAfter time.Duration) (*model.LoginAttempt, error)
+	LockLoginAttempt(ctx context.Context, key string, until time.Time) error
+	ResetLoginAttempt(ctx context.Context, key string) error
+}
+
+type LoginAttemptRepository struct {
+	DB *sql.DB
+}
+
+// loginAttemptSweepAfter is the longest reset window, after which LoginAttemptRepository drops an unlocked row.
+var loginAttemptSweepAfter = max(helper.CredentialLoginThrottle.ResetAfter, helper.IpLoginThrottle.ResetAfter)
+
+func NewLoginAttemptRepository(db *sql.DB) RepositoryLoginAttempt {
+	r := &LoginAttemptRepository{
+		DB: db,
+	}
+
+	// NOTE Rows are only deleted on a successful login otherwise, a failed sweep is retried on the next tick
+	go func() {
+		ticker := time.NewTicker(loginAttemptSweepInterval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			r.Sweep(now, loginAttemptSweepAfter)
+		}
+	}()
+
+	return r
+}
+
+// Sweep deletes the rows that are neither locked nor failed within resetAfter of now.
+func (r *LoginAttemptRepository) Sweep(now time.Time, resetAfter time.Duration) error {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, err := r.DB.ExecContext(context, `DELETE FROM login_attempts WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < $2)`, now.Add(-resetAfter), now)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) FindLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error) {
+
+	attempt := model.LoginAttempt{Key: key}
+	var lastFailedAt, lockedUntil sql.NullTime
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	err := r.DB.QueryRowContext(context, `SELECT failures, last_failed_at, locked_until FROM login_attempts WHERE key = $1`, key).Scan(&attempt.Failures, &lastFailedAt, &lockedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &attempt, nil
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	attempt.LastFailedAt = lastFailedAt.Time
+	attempt.LockedUntil = lockedUntil.Time
+
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, now time.Time, resetAfter time.Duration) (*model.LoginAttempt, error) {
+
+	attempt := model.LoginAttempt{Key: key, LastFailedAt: now}
+	var lockedUntil sql.NullTime
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `INSERT INTO login_attempts (key, failures, last_failed_at) VALUES ($1, 1, $2)
+	ON CONFLICT (key) DO UPDATE SET
+		failures = CASE WHEN login_attempts.last_failed_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		last_failed_at = EXCLUDED.last_failed_at
+	RETURNING failures, locked_until`
+
+	err := r.DB.QueryRowContext(context, query, key, now, now.Add(-resetAfter)).Scan(&attempt.Failures, &lockedUntil)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	attempt.LockedUntil = lockedUntil.Time
+
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) LockLoginAttempt(ctx context.Context, key string, until time.Time) error {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, err := r.DB.ExecContext(context, `UPDATE login_attempts SET locked_until = $1 WHERE key = $2`, until, key)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) ResetLoginAttempt(ctx context.Context, key string) error {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	_, err := r.DB.ExecContext(context, `DELETE FROM login_attempts WHERE key = $1`, key)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// loginAttemptSweepInterval is how often the login attempt stores drop stale entries.
+const loginAttemptSweepInterval = time.Minute
+
+// LoginAttemptMemoryRepository keeps failed logins in process memory, for a single instance deployment.
+type LoginAttemptMemoryRepository struct {
+	mu       sync.Mutex
+	attempts map[string]memoryLoginAttempt
+}
+
+type memoryLoginAttempt struct {
+	model.LoginAttempt
+	ResetAfter time.Duration
+}
+
+func NewLoginAttemptMemoryRepository() RepositoryLoginAttempt {
+	r := &LoginAttemptMemoryRepository{
+		attempts: make(map[string]memoryLoginAttempt),
+	}
+
+	// NOTE Stale entries are dropped on a timer so the map does not keep every key ever seen
+	go func() {
+		ticker := time.NewTicker(loginAttemptSweepInterval)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			r.Sweep(now)
+		}
+	}()
+
+	return r
+}
+
+// Sweep drops the entries that are neither locked nor within their reset window at now.
+func (r *LoginAttemptMemoryRepository) Sweep(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, attempt := range r.attempts {
+		if attempt.LastFailedAt.Before(now.Add(-attempt.ResetAfter)) && attempt.LockedUntil.Before(now) {
+			delete(r.attempts, key)
+		}
+	}
+}
+
+func (r *LoginAttemptMemoryRepository) FindLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt.Key = key
+	}
+
+	return &attempt.LoginAttempt, nil
+}
+
+func (r *LoginAttemptMemoryRepository) RecordLoginFailure(ctx context.Context, key string, now time.Time, resetAfter time.Duration) (*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt.Key = key
+	}
+
+	if attempt.LastFailedAt.Before(now.Add(-resetAfter)) {
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	attempt.ResetAfter = resetAfter
+	r.attempts[key] = attempt
+
+	return &attempt.LoginAttempt, nil
+}
+
+func (r *LoginAttemptMemoryRepository) LockLoginAttempt(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt.Key = key
+	}
+
+	attempt.LockedUntil = until
+	r.attempts[key] = attempt
+
+	return nil
+}
+
+func (r *LoginAttemptMemoryRepository) ResetLoginAttempt(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/pkg/errors"
+)
+
+type PasswordResetRepository struct {
+	DB *sql.DB
+}
+
+type RepositoryPasswordReset interface {
+	CreatePasswordReset(ctx context.Context, userId string, tokenHash string, expiresAt time.Time) error
+	FindPasswordReset(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	ResetPassword(ctx context.Context, reset *model.PasswordReset, passwordHash string) error
+}
+
+func NewPasswordResetRepository(db *sql.DB) RepositoryPasswordReset {
+	return &PasswordResetRepository{
+		DB: db,
+	}
+}
+
+// CreatePasswordReset stores a reset token for userId, previous unused tokens of the user stop working.
+// A token created less than model.PasswordResetResendInterval ago is reported as model.ErrPasswordResetTooSoon.
+func (r *PasswordResetRepository) CreatePasswordReset(ctx context.Context, userId string, tokenHash string, expiresAt time.Time) error {
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	// NOTE The user row is locked so concurrent requests see each other's token
+	_, err = tx.ExecContext(context, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	var recent bool
+	err = tx.QueryRowContext(context, `SELECT EXISTS (SELECT 1 FROM password_resets WHERE user_id = $1 AND created_at > $2)`, userId, dateTime.Add(-model.PasswordResetResendInterval)).Scan(&recent)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if recent {
+		err = model.ErrPasswordResetTooSoon
+		return err
+	}
+
+	_, err = tx.ExecContext(context, `DELETE FROM password_resets WHERE user_id = $1 AND used_at IS NULL`, userId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	_, err = tx.ExecContext(context, `INSERT INTO password_resets (user_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4)`, userId, tokenHash, expiresAt, dateTime)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// FindPasswordReset returns the unused, unexpired reset of tokenHash.
+func (r *PasswordResetRepository) FindPasswordReset(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+
+	var reset model.PasswordReset
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT id, user_id, expires_at FROM password_resets WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2`
+
+	err := r.DB.QueryRowContext(context, query, tokenHash, time.Now()).Scan(&reset.Id, &reset.UserId, &reset.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrInvalidPasswordReset
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &reset, nil
+}
+
+// ResetPassword consumes reset, replaces the user's password and revokes every session of the user.
+func (r *PasswordResetRepository) ResetPassword(ctx context.Context, reset *model.PasswordReset, passwordHash string) error {
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	result, err := tx.ExecContext(context, `UPDATE password_resets SET used_at = $1 WHERE id = $2 AND used_at IS NULL`, dateTime, reset.Id)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		err = model.ErrInvalidPasswordReset
+		return err
+	}
+
+	// NOTE Accounts waiting to be purged cannot be recovered with an outstanding token
+	result, err = tx.ExecContext(context, `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`, passwordHash, dateTime, reset.UserId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err = result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		err = model.ErrUserNotFound
+		return err
+	}
+
+	_, err = tx.ExecContext(context, `UPDATE sessions SET revoked_at = $1, updated_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`, dateTime, reset.UserId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
@@ -5,13 +5,13 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
 	"github.com/Dzikuri/openidea-segokuning/internal/model"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/pkg/errors"
 )
 
 type PostRepository struct {
@@ -20,9 +20,16 @@ type PostRepository struct {
 
 type RepositoryPost interface {
 	CreatePost(ctx context.Context, request *model.CreatePostRequest) (*model.PostResponse, error)
-	FindPostById(ctx context.Context, id string) (*model.PostResponse, error)
+	FindPostById(ctx context.Context, id string, viewerId string) (*model.PostResponse, error)
 	PostList(ctx context.Context, request *model.PostListRequest) ([]model.PostListResponse, model.MetaDataResponse, error)
 	CreatePostComment(ctx context.Context, request *model.CreatePostCommentRequest) (*model.PostCommentResponse, error)
+	FindPostCommentById(ctx context.Context, id string) (*model.PostCommentResponse, error)
+	PostCommentList(ctx context.Context, request *model.PostCommentListRequest) ([]model.PostCommentUserResponse, model.MetaDataResponse, error)
+	UpdatePostComment(ctx context.Context, request *model.UpdatePostCommentRequest) (*model.PostCommentResponse, error)
+	DeletePostComment(ctx context.Context, id string) error
+	UpdatePost(ctx context.Context, request *model.UpdatePostRequest) (*model.PostResponse, error)
+	DeletePost(ctx context.Context, id string, userId string) error
+	PostHistory(ctx context.Context, id string) ([]model.PostHistoryResponse, error)
 }
 
 func NewPostRepository(db *sql.DB) RepositoryPost {
@@ -31,6 +38,12 @@ func NewPostRepository(db *sql.DB) RepositoryPost {
 	}
 }
 
+// postVisibleTo returns a condition that is true when viewer may see the post
+// according to its visibility level.
+func postVisibleTo(viewer string) string {
+	return fmt.Sprintf(`(posts.user_id = %[1]s OR posts.visibility = '%[2]s' OR (posts.visibility = '%[3]s' AND EXISTS (SELECT 1 FROM friends WHERE friends.user_id = %[1]s AND friends.follow_user_id = posts.user_id)))`, viewer, model.PostVisibilityPublic, model.PostVisibilityFriends)
+}
+
 func (r *PostRepository) CreatePost(ctx context.Context, request *model.CreatePostRequest) (*model.PostResponse, error) {
 
 	var post model.PostResponse
@@ -38,9 +51,9 @@ func (r *PostRepository) CreatePost(ctx context.Context, request *model.CreatePo
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO posts (user_id, content, tags, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, user_id, content, tags`
+	query := `INSERT INTO posts (user_id, content, tags, visibility, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, user_id, content, tags, visibility, created_at, updated_at`
 
-	err := r.DB.QueryRowContext(context, query, request.UserId, request.Content, request.Tags, time.Now(), time.Now()).Scan(&post.Id, &post.UserId, &post.Content, &post.Tags)
+	err := r.DB.QueryRowContext(context, query, request.UserId, request.Content, request.Tags, request.Visibility, time.Now(), time.Now()).Scan(&post.Id, &post.UserId, &post.Content, &post.Tags, &post.Visibility, &post.CreatedAt, &post.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -49,7 +62,7 @@ func (r *PostRepository) CreatePost(ctx context.Context, request *model.CreatePo
 	return &post, nil
 }
 
-func (r *PostRepository) FindPostById(ctx context.Context, id string) (*model.PostResponse, error) {
+func (r *PostRepository) FindPostById(ctx context.Context, id string, viewerId string) (*model.PostResponse, error) {
 	var post model.PostResponse
 
 	validate := uuid.Validate(id)
@@ -60,9 +73,10 @@ func (r *PostRepository) FindPostById(ctx context.Context, id string) (*model.Po
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	query := `SELECT id, user_id, content, tags, created_at, updated_at FROM posts WHERE id = $1`
+	// NOTE Posts the viewer is not allowed to see are reported as not found
+	query := `SELECT id, user_id, content, tags, visibility, created_at, updated_at FROM posts WHERE id = $1 AND ` + postVisibleTo("$2")
 
-	err := r.DB.QueryRowContext(context, query, id).Scan(&post.Id, &post.UserId, &post.Content, &post.Tags, &post.CreatedAt, &post.UpdatedAt)
+	err := r.DB.QueryRowContext(context, query, id, viewerId).Scan(&post.Id, &post.UserId, &post.Content, &post.Tags, &post.Visibility, &post.CreatedAt, &post.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -81,10 +95,285 @@ func (r *PostRepository) CreatePostComment(ctx context.Context, request *model.C
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	query := `INSERT INTO post_comments (post_id, user_id, comment, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, post_id, user_id, comment`
+	query := `INSERT INTO post_comments (post_id, user_id, comment, parent_comment_id, depth, created_at, updated_at) VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7)
+	RETURNING id, post_id, user_id, COALESCE(parent_comment_id::text, ''), depth, comment, created_at, updated_at`
+
+	err := r.DB.QueryRowContext(context, query, request.PostId, request.UserId, request.Comment, request.ParentCommentId, request.Depth, time.Now(), time.Now()).Scan(&post.Id, &post.PostId, &post.UserId, &post.ParentCommentId, &post.Depth, &post.Comment, &post.CreatedAt, &post.UpdatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
+
+func (r *PostRepository) FindPostCommentById(ctx context.Context, id string) (*model.PostCommentResponse, error) {
+
+	var comment model.PostCommentResponse
+
+	if !helper.IsValidUUID(id) {
+		return nil, model.ErrCommentNotFound
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT id, post_id, user_id, COALESCE(parent_comment_id::text, ''), depth, comment, deleted_at IS NOT NULL, created_at, updated_at FROM post_comments WHERE id = $1`
+
+	err := r.DB.QueryRowContext(context, query, id).Scan(&comment.Id, &comment.PostId, &comment.UserId, &comment.ParentCommentId, &comment.Depth, &comment.Comment, &comment.IsDeleted, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrCommentNotFound
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if comment.IsDeleted {
+		comment.Comment = model.CommentRemovedMessage
+	}
+
+	return &comment, nil
+}
+
+func (r *PostRepository) PostCommentList(ctx context.Context, request *model.PostCommentListRequest) ([]model.PostCommentUserResponse, model.MetaDataResponse, error) {
+
+	comments := make([]model.PostCommentUserResponse, 0)
+	metaData := model.MetaDataResponse{
+		Limit: request.Limit,
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// NOTE Pages are made of top level comments, each one comes with its whole reply thread
+	query := NewQueryBuilder()
+	query.WhereEqual("post_comments.post_id", request.PostId)
+	query.Where("post_comments.parent_comment_id IS NULL")
+
+	viewer := query.Arg(request.UserId)
+	query.Where("NOT " + hiddenFromViewer("post_comments.user_id", viewer))
+
+	queryCountFrom := fmt.Sprintf("FROM post_comments %s", query.Condition())
+	countArgs := query.Args()
+
+	if request.Cursor != "" {
+		cursorCreatedAt, cursorId, err := helper.DecodeCursor(request.Cursor)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+
+		query.Where(fmt.Sprintf("(post_comments.created_at, post_comments.id) > (%s::timestamptz, %s::uuid)", query.Arg(cursorCreatedAt), query.Arg(cursorId)))
+	}
+
+	queryGet := fmt.Sprintf(`WITH RECURSIVE page AS (
+	SELECT post_comments.id FROM post_comments %[1]s
+	ORDER BY post_comments.created_at ASC, post_comments.id ASC
+	LIMIT %[2]s
+), thread AS (
+	SELECT page.id FROM page
+	UNION ALL
+	SELECT post_comments.id FROM post_comments JOIN thread ON post_comments.parent_comment_id = thread.id
+	WHERE NOT %[3]s
+)
+SELECT
+	post_comments.id,
+	post_comments.post_id,
+	post_comments.user_id,
+	COALESCE(post_comments.parent_comment_id::text, ''),
+	post_comments.depth,
+	COALESCE(CASE WHEN post_comments.deleted_at IS NULL THEN post_comments.comment END, ''),
+	post_comments.deleted_at IS NOT NULL,
+	post_comments.created_at,
+	post_comments.updated_at,
+	users.id,
+	users.name,
+	users.image_url,
+	users.total_friend,
+	users.created_at,
+	%[5]s AS reactions,
+	%[6]s AS my_reaction,
+	(SELECT count(*) %[4]s) AS total_count
+FROM
+	thread
+	JOIN post_comments ON post_comments.id = thread.id
+	JOIN users ON users.id = post_comments.user_id
+ORDER BY
+	post_comments.created_at ASC,
+	post_comments.id ASC;`, query.Condition(), query.Arg(request.Limit), hiddenFromViewer("post_comments.user_id", viewer), queryCountFrom,
+		reactionCounts("reactions.comment_id", "post_comments.id"), viewerReaction("reactions.comment_id", "post_comments.id", viewer))
+
+	rows, err := r.DB.QueryContext(context, queryGet, query.Args()...)
+	if err != nil {
+		return nil, model.MetaDataResponse{}, err
+	}
+	defer rows.Close()
+
+	roots := 0
+	var lastRoot model.PostCommentUserResponse
+	for rows.Next() {
+		var comment model.PostCommentUserResponse
+		var reactionJSON []byte
+		var myReaction sql.NullString
+
+		err = rows.Scan(
+			&comment.Id,
+			&comment.PostId,
+			&comment.UserId,
+			&comment.ParentCommentId,
+			&comment.Depth,
+			&comment.Comment,
+			&comment.IsDeleted,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+			&comment.Creator.UserId,
+			&comment.Creator.Name,
+			&comment.Creator.ImageUrl,
+			&comment.Creator.FriendCount,
+			&comment.Creator.CreatedAt,
+			&reactionJSON,
+			&myReaction,
+			&metaData.Total,
+		)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+
+		err = json.Unmarshal(reactionJSON, &comment.Reactions)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+		comment.MyReaction = model.ReactionType(myReaction.String)
+
+		if comment.IsDeleted {
+			comment.Comment = model.CommentRemovedMessage
+		}
+
+		if comment.ParentCommentId == "" {
+			roots++
+			lastRoot = comment
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, model.MetaDataResponse{}, err
+	}
+
+	// NOTE A page past the end has no row to carry the total, count it separately
+	if len(comments) == 0 && request.Cursor != "" {
+		err = r.DB.QueryRowContext(context, "SELECT count(*) "+queryCountFrom, countArgs...).Scan(&metaData.Total)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+	}
+
+	if request.Limit > 0 && roots == request.Limit {
+		metaData.NextCursor = helper.EncodeCursor(lastRoot.CreatedAt, lastRoot.Id)
+	}
+
+	return comments, metaData, nil
+}
+
+func (r *PostRepository) UpdatePostComment(ctx context.Context, request *model.UpdatePostCommentRequest) (*model.PostCommentResponse, error) {
+
+	var comment model.PostCommentResponse
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// NOTE Removed comments cannot be edited anymore
+	query := `UPDATE post_comments SET comment = $1, updated_at = $2 WHERE id = $3 AND user_id = $4 AND deleted_at IS NULL
+	RETURNING id, post_id, user_id, COALESCE(parent_comment_id::text, ''), depth, comment, created_at, updated_at`
+
+	err := r.DB.QueryRowContext(context, query, request.Comment, time.Now(), request.Id, request.UserId).Scan(&comment.Id, &comment.PostId, &comment.UserId, &comment.ParentCommentId, &comment.Depth, &comment.Comment, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrCommentNotFound
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &comment, nil
+}
+
+func (r *PostRepository) DeletePostComment(ctx context.Context, id string) error {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// NOTE Soft delete, the comment keeps its place in the thread
+	result, err := r.DB.ExecContext(context, `UPDATE post_comments SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		return model.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *PostRepository) UpdatePost(ctx context.Context, request *model.UpdatePostRequest) (*model.PostResponse, error) {
+
+	var post model.PostResponse
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// Start a transaction with context
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			// Rollback the transaction if an error occurred
+			tx.Rollback()
+			return
+		}
+	}()
+
+	// Lock the post and read the version that is about to be replaced
+	var previous model.PostHistoryResponse
+	querySelect := `SELECT id, content, tags, updated_at FROM posts WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	err = tx.QueryRowContext(context, querySelect, request.Id, request.UserId).Scan(&previous.PostId, &previous.Content, &previous.Tags, &previous.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrResNotFound.Error
+		}
+		return nil, err
+	}
+
+	// Keep the current version in the history before overwriting it
+	queryHistory := `INSERT INTO post_histories (post_id, content, tags, created_at, replaced_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err = tx.ExecContext(context, queryHistory, previous.PostId, previous.Content, previous.Tags, previous.CreatedAt, dateTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Empty content or tags keep the current value
+	var tags interface{}
+	if len(request.Tags) > 0 {
+		tags = pq.StringArray(request.Tags)
+	}
 
-	err := r.DB.QueryRowContext(context, query, request.PostId, request.UserId, request.Comment, time.Now(), time.Now()).Scan(&post.Id, &post.PostId, &post.UserId, &post.Comment)
+	queryUpdate := `UPDATE posts SET content = COALESCE(NULLIF($1, ''), content), tags = COALESCE($2::text[], tags), updated_at = $3 WHERE id = $4 AND user_id = $5
+	RETURNING id, user_id, content, tags, visibility, created_at, updated_at`
+	err = tx.QueryRowContext(context, queryUpdate, request.Content, tags, dateTime, request.Id, request.UserId).Scan(&post.Id, &post.UserId, &post.Content, &post.Tags, &post.Visibility, &post.CreatedAt, &post.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
 
+	// Commit the transaction if everything succeeded
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
@@ -92,93 +381,248 @@ func (r *PostRepository) CreatePostComment(ctx context.Context, request *model.C
 	return &post, nil
 }
 
+func (r *PostRepository) DeletePost(ctx context.Context, id string, userId string) error {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// NOTE Comments and histories are removed by ON DELETE CASCADE
+	result, err := r.DB.ExecContext(context, `DELETE FROM posts WHERE id = $1 AND user_id = $2`, id, userId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		return model.ErrResNotFound.Error
+	}
+
+	return nil
+}
+
+func (r *PostRepository) PostHistory(ctx context.Context, id string) ([]model.PostHistoryResponse, error) {
+
+	histories := make([]model.PostHistoryResponse, 0)
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT id, post_id, content, tags, created_at, replaced_at FROM post_histories WHERE post_id = $1 ORDER BY replaced_at DESC`
+
+	rows, err := r.DB.QueryContext(context, query, id)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var history model.PostHistoryResponse
+
+		err = rows.Scan(&history.Id, &history.PostId, &history.Content, &history.Tags, &history.CreatedAt, &history.ReplacedAt)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+
+		histories = append(histories, history)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return histories, nil
+}
+
+// DecodeComments decodes comments aggregated with json_agg/json_build_object,
+// using the JSON field names of model.PostCommentUserResponse.
+func DecodeComments(data []byte) ([]model.PostCommentUserResponse, error) {
+	comments := make([]model.PostCommentUserResponse, 0)
+	if len(data) == 0 {
+		return comments, nil
+	}
+
+	err := json.Unmarshal(data, &comments)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Soft deleted comments stay in the thread with a placeholder text
+	for i := range comments {
+		if comments[i].IsDeleted {
+			comments[i].Comment = model.CommentRemovedMessage
+		}
+	}
+
+	return comments, nil
+}
+
 func (r *PostRepository) PostList(ctx context.Context, request *model.PostListRequest) ([]model.PostListResponse, model.MetaDataResponse, error) {
 
-	var posts []model.PostListResponse
-	var metaData model.MetaDataResponse
+	posts := make([]model.PostListResponse, 0)
+	metaData := model.MetaDataResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
 
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	// queryCondition := fmt.Sprintf("WHERE posts.userd_id <> '%s' ", request.UserId)
-	queryCondition := fmt.Sprintf("")
+	query := NewQueryBuilder()
+
+	// NOTE Hide posts and comments of users blocking or muted by the viewer
+	viewer := query.Arg(request.UserId)
+	query.Where("NOT " + hiddenFromViewer("posts.user_id", viewer))
+	query.Where(postVisibleTo(viewer))
+
+	// NOTE The default feed only has the viewer's and their friends' posts and comments,
+	// only the latest model.FeedCommentLimit comments are embedded per post
+	queryCommentCondition := "AND NOT " + hiddenFromViewer("post_comments.user_id", viewer)
+	if !request.Explore {
+		query.Where(selfOrFriend("posts.user_id", viewer))
+		queryCommentCondition += " AND " + selfOrFriend("post_comments.user_id", viewer)
+	}
+
+	if request.PostId != "" {
+		query.WhereEqual("posts.id", request.PostId)
+	}
+
 	if request.Search != "" {
-		if queryCondition == "" {
-			queryCondition += " WHERE"
-		} else {
-			queryCondition += " AND"
-		}
-		queryCondition += " posts.content LIKE '%" + request.Search + "%'"
+		query.WhereLike("posts.content", request.Search)
 	}
 
 	if len(request.SearchTag) > 0 {
-		jsonTag, err := json.Marshal([]string(request.SearchTag))
-		if err == nil {
-			replacer := strings.NewReplacer("[", "{", "]", "}")
-			stringTag := replacer.Replace(string(jsonTag))
+		query.WhereOverlap("posts.tags", request.SearchTag)
+	}
 
-			if queryCondition == "" {
-				queryCondition += " WHERE "
-			} else {
-				queryCondition += " AND "
-			}
+	// NOTE Total is counted with the filters only, before the cursor narrows the rows
+	queryCountFrom := fmt.Sprintf("FROM posts JOIN users ON posts.user_id = users.id %s", query.Condition())
+	countArgs := query.Args()
 
-			queryCondition += fmt.Sprintf(" posts.tags && '%s'", stringTag)
+	// NOTE Keyset pagination when a cursor is supplied, offset pagination otherwise
+	queryPaginate := ""
+	if request.Cursor != "" {
+		cursorCreatedAt, cursorId, err := helper.DecodeCursor(request.Cursor)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
 		}
+
+		query.Where(fmt.Sprintf("(posts.created_at, posts.id) < (%s::timestamptz, %s::uuid)", query.Arg(cursorCreatedAt), query.Arg(cursorId)))
+		queryPaginate = fmt.Sprintf("LIMIT %s", query.Arg(request.Limit))
+	} else {
+		queryPaginate = fmt.Sprintf("LIMIT %s OFFSET %s", query.Arg(request.Limit), query.Arg(request.Offset))
 	}
 
-	queryGet := fmt.Sprintf(`SELECT DISTINCT
+	queryGet := fmt.Sprintf(`SELECT
 	posts."id",
 	posts.user_id,
 	posts."content",
 	posts.tags,
+	posts.visibility,
 	posts.created_at,
 	users.ID AS post_creator_user_id,
 	users."name" AS post_creator_user_name,
 	users.total_friend AS post_creator_total_friend,
 	users.image_url AS post_creator_image_url,
-	ARRAY (
+	COALESCE((
+	SELECT
+		json_agg(json_build_object(
+			'id', latest_comments.id,
+			'postId', latest_comments.post_id,
+			'userId', latest_comments.user_id,
+			'parentCommentId', latest_comments.parent_comment_id,
+			'depth', latest_comments.depth,
+			'comment', latest_comments.comment,
+			'isDeleted', latest_comments.is_deleted,
+			'createdAt', latest_comments.created_at,
+			'updatedAt', latest_comments.updated_at,
+			'reactions', latest_comments.reactions,
+			'myReaction', latest_comments.my_reaction,
+			'creator', json_build_object(
+				'userId', latest_comments.creator_id,
+				'name', latest_comments.creator_name,
+				'imageUrl', latest_comments.creator_image_url,
+				'friendCount', latest_comments.creator_total_friend,
+				'createdAt', latest_comments.creator_created_at
+			)
+		) ORDER BY latest_comments.created_at ASC)
+	FROM (
 	SELECT
-		(
-			post_comments."id" || ',' || post_comments.post_id || ',' || post_comments."user_id" || ',' || post_comments.COMMENT || ',' || post_comments.created_at || ',' || users."id"  || ',' || users.NAME || ',' || users.image_url  || ',' || users.total_friend || ',' || users.created_at 
-		) 
+		post_comments.id,
+		post_comments.post_id,
+		post_comments.user_id,
+		post_comments.parent_comment_id,
+		post_comments.depth,
+		CASE WHEN post_comments.deleted_at IS NULL THEN post_comments.comment ELSE '' END AS comment,
+		post_comments.deleted_at IS NOT NULL AS is_deleted,
+		post_comments.created_at,
+		post_comments.updated_at,
+		users.id AS creator_id,
+		users.name AS creator_name,
+		users.image_url AS creator_image_url,
+		users.total_friend AS creator_total_friend,
+		users.created_at AS creator_created_at,
+		%[6]s AS reactions,
+		%[7]s AS my_reaction
 	FROM
 		post_comments
 		JOIN users ON post_comments.user_id = users.ID 
 	WHERE
 		posts.ID = post_comments.post_id 
-	) AS comments_post 
+		%[1]s
+	ORDER BY
+		post_comments.created_at DESC
+	LIMIT %[2]d
+	) AS latest_comments
+	), '[]') AS comments_post,
+	(SELECT count(*) FROM post_comments WHERE posts.ID = post_comments.post_id %[1]s) AS comment_count,
+	%[8]s AS reactions,
+	%[9]s AS my_reaction,
+	(SELECT count(*) %[3]s) AS total_count
 FROM
 	posts
-	JOIN users ON posts.user_id = users.
-	ID LEFT JOIN post_comments ON post_comments.post_id = posts."id" 
-    %s
+	JOIN users ON posts.user_id = users.ID
+    %[4]s
 ORDER BY
-	posts.created_at DESC 
-	LIMIT %d OFFSET %d;`, queryCondition, request.Limit, request.Offset)
+	posts.created_at DESC,
+	posts."id" DESC
+	%[5]s;`, queryCommentCondition, model.FeedCommentLimit, queryCountFrom, query.Condition(), queryPaginate,
+		reactionCounts("reactions.comment_id", "post_comments.id"), viewerReaction("reactions.comment_id", "post_comments.id", viewer),
+		reactionCounts("reactions.post_id", "posts.id"), viewerReaction("reactions.post_id", "posts.id", viewer))
 
-	rows, err := r.DB.QueryContext(context, queryGet)
+	rows, err := r.DB.QueryContext(context, queryGet, query.Args()...)
 	if err != nil {
 		return nil, model.MetaDataResponse{}, err
 	}
+	defer rows.Close()
 
-	totalRows := 0
+	var lastCreatedAt time.Time
 	for rows.Next() {
 
 		var post model.PostListResponse
 		var createdAt time.Time
-		postCommentString := make([]string, 0)
+		var postCommentJSON []byte
+		var postReactionJSON []byte
+		var myReaction sql.NullString
 		err := rows.Scan(
 			&post.PostId,
 			&post.Post.UserId,
 			&post.Post.Content,
 			&post.Post.Tags,
+			&post.Post.Visibility,
 			&createdAt,
 			&post.Creator.UserId,
 			&post.Creator.Name,
 			&post.Creator.FriendCount,
 			&post.Creator.ImageUrl,
-			pq.Array(&postCommentString),
+			&postCommentJSON,
+			&post.CommentCount,
+			&postReactionJSON,
+			&myReaction,
+			&metaData.Total,
 		)
 		if err != nil {
 			return nil, model.MetaDataResponse{}, err
@@ -186,37 +630,37 @@ ORDER BY
 
 		post.Post.CreatedAt = createdAt
 
-		postComment := make([]model.PostCommentUserResponse, 0)
-		for i := 0; i < (len(postCommentString)); i++ {
-			cArray := strings.Split(postCommentString[i], ",")
+		post.Comments, err = DecodeComments(postCommentJSON)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
 
-			var comment model.PostCommentUserResponse
+		err = json.Unmarshal(postReactionJSON, &post.Reactions)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
+		}
+		post.MyReaction = model.ReactionType(myReaction.String)
 
-			comment.Id = cArray[0]
-			comment.PostId = cArray[1]
-			comment.UserId = cArray[2]
-			comment.Comment = cArray[3]
+		posts = append(posts, post)
 
-			comment.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", cArray[4])
+		lastCreatedAt = createdAt
+	}
 
-			comment.Creator.UserId = cArray[5]
-			comment.Creator.Name = cArray[6]
-			comment.Creator.ImageUrl = cArray[7]
-			friendCount, _ := strconv.Atoi(cArray[8])
-			comment.Creator.FriendCount = friendCount
-			comment.Creator.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", cArray[9])
+	if err = rows.Err(); err != nil {
+		return nil, model.MetaDataResponse{}, err
+	}
 
-			postComment = append(postComment, comment)
+	// NOTE A page past the end has no row to carry the total, count it separately
+	if len(posts) == 0 && (request.Offset > 0 || request.Cursor != "") {
+		err = r.DB.QueryRowContext(context, "SELECT count(*) "+queryCountFrom, countArgs...).Scan(&metaData.Total)
+		if err != nil {
+			return nil, model.MetaDataResponse{}, err
 		}
-
-		post.Comments = postComment
-
-		posts = append(posts, post)
-
-		totalRows++
 	}
 
-	rows.Close()
+	if request.Limit > 0 && len(posts) == request.Limit {
+		metaData.NextCursor = helper.EncodeCursor(lastCreatedAt, posts[len(posts)-1].PostId)
+	}
 
 	return posts, metaData, nil
 }
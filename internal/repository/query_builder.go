@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// QueryBuilder collects WHERE conditions and their arguments so every
+// user supplied value is sent to postgres as a positional $n parameter
+// instead of being spliced into the query text.
+type QueryBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{
+		conditions: make([]string, 0),
+		args:       make([]interface{}, 0),
+	}
+}
+
+// Arg registers a value and returns its placeholder, e.g. "$3".
+func (q *QueryBuilder) Arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// Where appends a raw condition. Values inside the condition must come from Arg.
+func (q *QueryBuilder) Where(condition string) *QueryBuilder {
+	q.conditions = append(q.conditions, condition)
+	return q
+}
+
+// WhereEqual appends "column = $n".
+func (q *QueryBuilder) WhereEqual(column string, value interface{}) *QueryBuilder {
+	return q.Where(fmt.Sprintf("%s = %s", column, q.Arg(value)))
+}
+
+// WhereNotEqual appends "column <> $n".
+func (q *QueryBuilder) WhereNotEqual(column string, value interface{}) *QueryBuilder {
+	return q.Where(fmt.Sprintf("%s <> %s", column, q.Arg(value)))
+}
+
+// WhereLike appends a "contains" match. Wildcards inside search are escaped
+// so they are matched literally.
+func (q *QueryBuilder) WhereLike(column string, search string) *QueryBuilder {
+	return q.Where(fmt.Sprintf("%s LIKE %s", column, q.Arg("%"+EscapeLike(search)+"%")))
+}
+
+// WhereOverlap appends "column && $n::text[]" for text[] columns.
+func (q *QueryBuilder) WhereOverlap(column string, values []string) *QueryBuilder {
+	return q.Where(fmt.Sprintf("%s && %s::text[]", column, q.Arg(pq.StringArray(values))))
+}
+
+// Condition returns the conditions joined with AND, prefixed with WHERE,
+// or an empty string when there is nothing to filter on.
+func (q *QueryBuilder) Condition() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(q.conditions, " AND ") + " "
+}
+
+// Args returns the arguments in placeholder order.
+func (q *QueryBuilder) Args() []interface{} {
+	return q.args
+}
+
+// EscapeLike escapes the LIKE wildcards using postgres' default escape character.
+func EscapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/pkg/errors"
+)
+
+type ReactionRepository struct {
+	DB *sql.DB
+}
+
+type RepositoryReaction interface {
+	React(ctx context.Context, request model.ReactionRequest) (*model.ReactionResponse, error)
+	RemoveReaction(ctx context.Context, request model.ReactionRequest) error
+}
+
+func NewReactionRepository(db *sql.DB) RepositoryReaction {
+	return &ReactionRepository{
+		DB: db,
+	}
+}
+
+// reactionCounts returns a subquery building a {"type": count} JSON object
+// for the reactions of targetColumn (reactions.post_id or reactions.comment_id) = target.
+func reactionCounts(targetColumn string, target string) string {
+	return fmt.Sprintf(`COALESCE((SELECT json_object_agg(target_reactions.type, target_reactions.total) FROM (SELECT reactions.type, count(*) AS total FROM reactions WHERE %s = %s GROUP BY reactions.type) AS target_reactions), '{}'::json)`, targetColumn, target)
+}
+
+// viewerReaction returns a subquery selecting the reaction type viewer left on target, or NULL.
+func viewerReaction(targetColumn string, target string, viewer string) string {
+	return fmt.Sprintf(`(SELECT reactions.type FROM reactions WHERE %s = %s AND reactions.user_id = %s)`, targetColumn, target, viewer)
+}
+
+// reactionTarget returns the column and value the request points at.
+func reactionTarget(request model.ReactionRequest) (string, string) {
+	if request.PostId != "" {
+		return "post_id", request.PostId
+	}
+
+	return "comment_id", request.CommentId
+}
+
+func (r *ReactionRepository) React(ctx context.Context, request model.ReactionRequest) (*model.ReactionResponse, error) {
+
+	var reaction model.ReactionResponse
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	column, target := reactionTarget(request)
+
+	// NOTE Reacting again replaces the previous reaction type
+	query := fmt.Sprintf(`INSERT INTO reactions (user_id, %[1]s, type, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)
+	ON CONFLICT (user_id, %[1]s) WHERE %[1]s IS NOT NULL DO UPDATE SET type = EXCLUDED.type, updated_at = EXCLUDED.updated_at
+	RETURNING id, user_id, COALESCE(post_id::text, ''), COALESCE(comment_id::text, ''), type, created_at, updated_at`, column)
+
+	err := r.DB.QueryRowContext(context, query, request.UserId, target, request.Type, time.Now()).Scan(&reaction.Id, &reaction.UserId, &reaction.PostId, &reaction.CommentId, &reaction.Type, &reaction.CreatedAt, &reaction.UpdatedAt)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &reaction, nil
+}
+
+func (r *ReactionRepository) RemoveReaction(ctx context.Context, request model.ReactionRequest) error {
+
+	column, target := reactionTarget(request)
+	if !helper.IsValidUUID(target) {
+		return model.ErrReactionNotFound
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(context, fmt.Sprintf(`DELETE FROM reactions WHERE user_id = $1 AND %s = $2`, column), request.UserId, target)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		return model.ErrReactionNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/pkg/errors"
+)
+
+type SessionRepository struct {
+	DB *sql.DB
+}
+
+type RepositorySession interface {
+	CreateSession(ctx context.Context, request model.Session, tokenHash string, expiresAt time.Time) (*model.Session, error)
+	FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, token *model.RefreshToken, tokenHash string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, sessionId string, userId string) error
+	IsSessionActive(ctx context.Context, sessionId string, userId string) (bool, error)
+	TouchSession(ctx context.Context, sessionId string) error
+	FindAllSession(ctx context.Context, userId string) ([]model.Session, error)
+}
+
+func NewSessionRepository(db *sql.DB) RepositorySession {
+	return &SessionRepository{
+		DB: db,
+	}
+}
+
+// sessionLive returns a condition that is true when the session is not revoked and can still be refreshed at now.
+// NOTE A session whose refresh tokens all expired was abandoned, it counts as ended without being revoked
+func sessionLive(now string) string {
+	return `sessions.revoked_at IS NULL AND EXISTS (SELECT 1 FROM refresh_tokens WHERE refresh_tokens.session_id = sessions.id AND refresh_tokens.used_at IS NULL AND refresh_tokens.expires_at > ` + now + `)`
+}
+
+// CreateSession starts a session for request.UserId together with its first refresh token.
+func (r *SessionRepository) CreateSession(ctx context.Context, request model.Session, tokenHash string, expiresAt time.Time) (*model.Session, error) {
+
+	var session model.Session
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	querySession := `INSERT INTO sessions (user_id, user_agent, ip_address, last_seen_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $4, $4)
+	RETURNING id, user_id, user_agent, ip_address, last_seen_at, created_at, updated_at`
+	err = tx.QueryRowContext(context, querySession, request.UserId, request.UserAgent, request.IpAddress, dateTime).Scan(&session.Id, &session.UserId, &session.UserAgent, &session.IpAddress, &session.LastSeenAt, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	queryToken := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(context, queryToken, session.Id, tokenHash, expiresAt, dateTime)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &session, nil
+}
+
+func (r *SessionRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+
+	var token model.RefreshToken
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT refresh_tokens.id, refresh_tokens.session_id, sessions.user_id, refresh_tokens.used_at, refresh_tokens.expires_at, sessions.revoked_at IS NOT NULL
+	FROM refresh_tokens JOIN sessions ON refresh_tokens.session_id = sessions.id WHERE refresh_tokens.token_hash = $1`
+
+	err := r.DB.QueryRowContext(context, query, tokenHash).Scan(&token.Id, &token.SessionId, &token.UserId, &token.UsedAt, &token.ExpiresAt, &token.SessionRevoked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrInvalidRefreshToken
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken marks token as used and stores its replacement in the same session.
+// A token that was already used by a concurrent request is reported as model.ErrRefreshTokenReused.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, token *model.RefreshToken, tokenHash string, expiresAt time.Time) error {
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	result, err := tx.ExecContext(context, `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL`, dateTime, token.Id)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		err = model.ErrRefreshTokenReused
+		return err
+	}
+
+	queryToken := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(context, queryToken, token.SessionId, tokenHash, expiresAt, dateTime)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionId string, userId string) error {
+
+	if !helper.IsValidUUID(sessionId) {
+		return model.ErrSessionNotFound
+	}
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(context, `UPDATE sessions SET revoked_at = $1, updated_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`, dateTime, sessionId, userId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		return model.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// IsSessionActive reports whether the session of userId is not revoked and not abandoned.
+func (r *SessionRepository) IsSessionActive(ctx context.Context, sessionId string, userId string) (bool, error) {
+
+	var active bool
+
+	if !helper.IsValidUUID(sessionId) {
+		return false, nil
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1 AND user_id = $2 AND ` + sessionLive("$3") + `)`
+
+	err := r.DB.QueryRowContext(context, query, sessionId, userId, time.Now()).Scan(&active)
+	if err != nil {
+		return false, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return active, nil
+}
+
+// TouchSession records the session as seen now.
+// NOTE last_seen_at is written at most once a minute to keep authenticated reads cheap
+func (r *SessionRepository) TouchSession(ctx context.Context, sessionId string) error {
+
+	if !helper.IsValidUUID(sessionId) {
+		return nil
+	}
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `UPDATE sessions SET last_seen_at = $1 WHERE id = $2 AND revoked_at IS NULL
+	AND (last_seen_at IS NULL OR last_seen_at < $1::timestamptz - interval '1 minute')`
+
+	_, err := r.DB.ExecContext(context, query, dateTime, sessionId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// FindAllSession lists the live sessions of userId, most recently seen first.
+func (r *SessionRepository) FindAllSession(ctx context.Context, userId string) ([]model.Session, error) {
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `SELECT id, user_id, user_agent, ip_address, last_seen_at, created_at, updated_at FROM sessions
+	WHERE user_id = $1 AND ` + sessionLive("$2") + ` ORDER BY COALESCE(last_seen_at, created_at) DESC`
+
+	rows, err := r.DB.QueryContext(context, query, userId, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer rows.Close()
+
+	sessions := make([]model.Session, 0)
+	for rows.Next() {
+		var session model.Session
+
+		err = rows.Scan(&session.Id, &session.UserId, &session.UserAgent, &session.IpAddress, &session.LastSeenAt, &session.CreatedAt, &session.UpdatedAt)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return sessions, nil
+}
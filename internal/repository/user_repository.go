@@ -16,11 +16,13 @@ import (
 )
 
 type RepositoryUser interface {
-	Register(ctx context.Context, user *model.UserAuthRequest) (*model.UserResponse, error)
-	FindByPhone(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.UserResponse, err error)
-	FindByEmail(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.UserResponse, err error)
-	FindById(ctx context.Context, id string) (res *model.UserResponse, code int, err error)
-	UpdateUserData(ctx context.Context, request model.UserResponse) (*model.UserResponse, error)
+	Register(ctx context.Context, user *model.UserAuthRequest) (*model.User, error)
+	FindByPhone(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.User, err error)
+	FindByEmail(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.User, err error)
+	FindById(ctx context.Context, id string) (res *model.User, code int, err error)
+	UpdateUserData(ctx context.Context, request model.User) (*model.User, error)
+	FindProfile(ctx context.Context, id string, viewerId string) (*model.UserProfileResponse, error)
+	UpdatePassword(ctx context.Context, id string, passwordHash string, exceptSessionId string) error
 }
 
 type UserRepository struct {
@@ -33,7 +35,7 @@ func NewUserRepository(db *sql.DB) RepositoryUser {
 	}
 }
 
-func (r *UserRepository) Register(ctx context.Context, user *model.UserAuthRequest) (*model.UserResponse, error) {
+func (r *UserRepository) Register(ctx context.Context, user *model.UserAuthRequest) (*model.User, error) {
 
 	queryCreate := ""
 	if user.CredentialType == model.Email {
@@ -66,7 +68,7 @@ func (r *UserRepository) Register(ctx context.Context, user *model.UserAuthReque
 		return nil, err
 	}
 
-	res := new(model.UserResponse)
+	res := new(model.User)
 	if user.CredentialType == model.Email {
 		res.Email = sql.NullString{user.CredentialValue, true}
 		res.Phone = sql.NullString{}
@@ -83,14 +85,14 @@ func (r *UserRepository) Register(ctx context.Context, user *model.UserAuthReque
 	return res, nil
 }
 
-func (r *UserRepository) FindByPhone(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.UserResponse, err error) {
+func (r *UserRepository) FindByPhone(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.User, err error) {
 	helper.LogPretty(user)
-	querySelect := fmt.Sprintf("SELECT id, email, phone, name, password, created_at, updated_at FROM users WHERE phone = $1")
+	querySelect := fmt.Sprintf("SELECT id, email, phone, name, password, created_at, updated_at FROM users WHERE phone = $1 AND deleted_at IS NULL")
 
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	result := &model.UserResponse{}
+	result := &model.User{}
 	row := r.DB.QueryRowContext(context, querySelect, user.CredentialValue)
 
 	errRowScan := row.Scan(&result.Id, &result.Email, &result.Phone, &result.Name, &result.Password, &result.CreatedAt, &result.UpdatedAt)
@@ -105,13 +107,13 @@ func (r *UserRepository) FindByPhone(ctx context.Context, user *model.UserAuthRe
 	return true, result, nil
 }
 
-func (r *UserRepository) FindByEmail(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.UserResponse, err error) {
-	querySelect := fmt.Sprintf("SELECT id, email, phone, name, password, created_at, updated_at FROM users WHERE email = $1")
+func (r *UserRepository) FindByEmail(ctx context.Context, user *model.UserAuthRequest) (exists bool, res *model.User, err error) {
+	querySelect := fmt.Sprintf("SELECT id, email, phone, name, password, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL")
 
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	result := &model.UserResponse{}
+	result := &model.User{}
 	row := r.DB.QueryRowContext(context, querySelect, user.CredentialValue)
 
 	errRowScan := row.Scan(&result.Id, &result.Email, &result.Phone, &result.Name, &result.Password, &result.CreatedAt, &result.UpdatedAt)
@@ -126,14 +128,14 @@ func (r *UserRepository) FindByEmail(ctx context.Context, user *model.UserAuthRe
 	return true, result, nil
 }
 
-func (r *UserRepository) FindById(ctx context.Context, id string) (res *model.UserResponse, code int, err error) {
+func (r *UserRepository) FindById(ctx context.Context, id string) (res *model.User, code int, err error) {
 
-	var user model.UserResponse
+	var user model.User
 
 	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	row := r.DB.QueryRowContext(context, "SELECT id, email, phone, name,  password, created_at, updated_at FROM users WHERE id = $1", id)
+	row := r.DB.QueryRowContext(context, "SELECT id, email, phone, name,  password, created_at, updated_at FROM users WHERE id = $1 AND deleted_at IS NULL", id)
 
 	err = row.Scan(&user.Id, &user.Email, &user.Phone, &user.Name, &user.Password, &user.CreatedAt, &user.UpdatedAt)
 
@@ -147,20 +149,21 @@ func (r *UserRepository) FindById(ctx context.Context, id string) (res *model.Us
 	return &user, http.StatusOK, nil
 }
 
-func (r *UserRepository) UpdateUserData(ctx context.Context, request model.UserResponse) (*model.UserResponse, error) {
+func (r *UserRepository) UpdateUserData(ctx context.Context, request model.User) (*model.User, error) {
 
 	queryUpdate := "UPDATE users SET"
 	var values []interface{}
 	counter := 1
 
+	// NOTE A valid but empty email or phone removes it
 	if request.Email.Valid {
-		queryUpdate += fmt.Sprintf(" email = $%d,", counter)
+		queryUpdate += fmt.Sprintf(" email = NULLIF($%d, ''),", counter)
 		values = append(values, request.Email)
 		counter++
 	}
 
 	if request.Phone.Valid {
-		queryUpdate += fmt.Sprintf(" phone = $%d,", counter)
+		queryUpdate += fmt.Sprintf(" phone = NULLIF($%d, ''),", counter)
 		values = append(values, request.Phone)
 		counter++
 	}
@@ -194,6 +197,15 @@ func (r *UserRepository) UpdateUserData(ctx context.Context, request model.UserR
 
 	result, err := r.DB.ExecContext(context, queryUpdate, values...)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return nil, model.ErrUserAlreadyExists
+			case "23514":
+				return nil, model.ErrLastCredential
+			}
+		}
 		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
 	}
 
@@ -209,3 +221,91 @@ func (r *UserRepository) UpdateUserData(ctx context.Context, request model.UserR
 
 	return nil, nil
 }
+
+// FindProfile loads the profile of id as seen by viewerId. Users who blocked the viewer or deleted their account are reported as not found.
+func (r *UserRepository) FindProfile(ctx context.Context, id string, viewerId string) (*model.UserProfileResponse, error) {
+
+	var profile model.UserProfileResponse
+
+	if !helper.IsValidUUID(id) {
+		return nil, model.ErrUserNotFound
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := fmt.Sprintf(`SELECT
+	users.id,
+	users.name,
+	COALESCE(users.image_url, ''),
+	COALESCE(users.total_friend, 0),
+	users.created_at,
+	(SELECT count(*) FROM friends AS mine JOIN friends AS theirs ON mine.follow_user_id = theirs.follow_user_id WHERE mine.user_id = $2 AND theirs.user_id = users.id) AS mutual_friend_count,
+	EXISTS (SELECT 1 FROM user_blocks WHERE user_blocks.user_id = $2 AND user_blocks.blocked_user_id = users.id AND user_blocks.type = '%[1]s') AS blocked,
+	EXISTS (SELECT 1 FROM friends WHERE friends.user_id = $2 AND friends.follow_user_id = users.id) AS friends,
+	EXISTS (SELECT 1 FROM friend_requests WHERE friend_requests.sender_id = $2 AND friend_requests.receiver_id = users.id AND friend_requests.status = '%[2]s') AS request_sent,
+	EXISTS (SELECT 1 FROM friend_requests WHERE friend_requests.sender_id = users.id AND friend_requests.receiver_id = $2 AND friend_requests.status = '%[2]s') AS request_received
+FROM
+	users
+WHERE
+	users.id = $1
+	AND users.deleted_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM user_blocks WHERE user_blocks.user_id = users.id AND user_blocks.blocked_user_id = $2 AND user_blocks.type = '%[1]s')`,
+		model.BlockTypeBlock, model.FriendRequestPending)
+
+	err := r.DB.QueryRowContext(context, query, id, viewerId).Scan(&profile.Id, &profile.Name, &profile.ImageUrl, &profile.FriendCount, &profile.CreatedAt, &profile.MutualFriendCount, &profile.Relation.Blocked, &profile.Relation.Friends, &profile.Relation.RequestSent, &profile.Relation.RequestReceived)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &profile, nil
+}
+
+// UpdatePassword replaces the password of id and revokes its sessions except exceptSessionId, in one transaction.
+func (r *UserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string, exceptSessionId string) error {
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.ExecContext(context, `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`, passwordHash, dateTime, id)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		err = model.ErrUserNotFound
+		return err
+	}
+
+	// NOTE Other devices have to log in again with the new password
+	_, err = tx.ExecContext(context, `UPDATE sessions SET revoked_at = $1, updated_at = $1 WHERE user_id = $2 AND revoked_at IS NULL AND id::text <> $3`, dateTime, id, exceptSessionId)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
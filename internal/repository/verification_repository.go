@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+)
+
+type VerificationRepository struct {
+	DB *sql.DB
+}
+
+type RepositoryVerification interface {
+	CreateCredentialVerification(ctx context.Context, request model.CredentialVerification) (*model.CredentialVerification, error)
+	ClaimVerificationAttempt(ctx context.Context, userId string, credentialType model.UserCredentialType) (*model.CredentialVerification, error)
+	ConfirmCredentialVerification(ctx context.Context, verification *model.CredentialVerification) error
+}
+
+func NewVerificationRepository(db *sql.DB) RepositoryVerification {
+	return &VerificationRepository{
+		DB: db,
+	}
+}
+
+// credentialColumn returns the users column holding a credential type.
+func credentialColumn(credentialType model.UserCredentialType) string {
+	if credentialType == model.Phone {
+		return "phone"
+	}
+
+	return "email"
+}
+
+// CreateCredentialVerification replaces any pending verification of the same user and credential type.
+// The pending row is reused so its invalid attempts carry over, see model.ReissueVerification.
+func (r *VerificationRepository) CreateCredentialVerification(ctx context.Context, request model.CredentialVerification) (*model.CredentialVerification, error) {
+
+	verification := request
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	var previous *model.CredentialVerification
+	var pending model.CredentialVerification
+	queryPending := `SELECT id, attempts, COALESCE(sent_at, created_at), created_at FROM credential_verifications WHERE user_id = $1 AND credential_type = $2 AND consumed_at IS NULL FOR UPDATE`
+	err = tx.QueryRowContext(context, queryPending, request.UserId, request.CredentialType).Scan(&pending.Id, &pending.Attempts, &pending.SentAt, &pending.CreatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	if err == nil {
+		previous = &pending
+	}
+
+	verification.Attempts, verification.CreatedAt, err = model.ReissueVerification(previous, dateTime)
+	if err != nil {
+		return nil, err
+	}
+	verification.SentAt = dateTime
+
+	if previous != nil {
+		verification.Id = previous.Id
+		queryUpdate := `UPDATE credential_verifications SET credential_value = $1, code_hash = $2, attempts = $3, expires_at = $4, sent_at = $5, created_at = $6 WHERE id = $7`
+		_, err = tx.ExecContext(context, queryUpdate, request.CredentialValue, request.CodeHash, verification.Attempts, request.ExpiresAt, verification.SentAt, verification.CreatedAt, verification.Id)
+		if err != nil {
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+	} else {
+		queryInsert := `INSERT INTO credential_verifications (user_id, credential_type, credential_value, code_hash, expires_at, sent_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $6) RETURNING id`
+		err = tx.QueryRowContext(context, queryInsert, request.UserId, request.CredentialType, request.CredentialValue, request.CodeHash, request.ExpiresAt, dateTime).Scan(&verification.Id)
+		if err != nil {
+			// NOTE A concurrent request created the pending row first
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return nil, model.ErrVerificationResendTooSoon
+			}
+			return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return &verification, nil
+}
+
+// ClaimVerificationAttempt counts one attempt against the pending verification and returns it.
+// The attempt is claimed before the code is compared, so concurrent guesses cannot exceed VerificationCodeMaxAttempts.
+func (r *VerificationRepository) ClaimVerificationAttempt(ctx context.Context, userId string, credentialType model.UserCredentialType) (*model.CredentialVerification, error) {
+
+	var verification model.CredentialVerification
+
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	query := `UPDATE credential_verifications SET attempts = attempts + 1
+	WHERE user_id = $1 AND credential_type = $2 AND consumed_at IS NULL AND attempts < $3
+	RETURNING id, user_id, credential_type, credential_value, code_hash, attempts, expires_at, COALESCE(sent_at, created_at), created_at`
+
+	err := r.DB.QueryRowContext(context, query, userId, credentialType, model.VerificationCodeMaxAttempts).Scan(&verification.Id, &verification.UserId, &verification.CredentialType, &verification.CredentialValue, &verification.CodeHash, &verification.Attempts, &verification.ExpiresAt, &verification.SentAt, &verification.CreatedAt)
+	if err == nil {
+		return &verification, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	// NOTE Nothing was claimed, either there is no pending verification or it ran out of attempts
+	var exists bool
+	err = r.DB.QueryRowContext(context, `SELECT EXISTS (SELECT 1 FROM credential_verifications WHERE user_id = $1 AND credential_type = $2 AND consumed_at IS NULL)`, userId, credentialType).Scan(&exists)
+	if err != nil {
+		return nil, errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if exists {
+		return nil, model.ErrVerificationAttemptsExceeded
+	}
+
+	return nil, model.ErrVerificationNotFound
+}
+
+// ConfirmCredentialVerification consumes the verification and writes its credential to the users row.
+func (r *VerificationRepository) ConfirmCredentialVerification(ctx context.Context, verification *model.CredentialVerification) error {
+
+	dateTime := time.Now()
+	context, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	tx, err := r.DB.BeginTx(context, nil)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	result, err := tx.ExecContext(context, `UPDATE credential_verifications SET consumed_at = $1 WHERE id = $2 AND consumed_at IS NULL`, dateTime, verification.Id)
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	if row == 0 {
+		err = model.ErrVerificationNotFound
+		return err
+	}
+
+	queryUpdate := fmt.Sprintf(`UPDATE users SET %s = $1, updated_at = $2 WHERE id = $3`, credentialColumn(verification.CredentialType))
+	_, err = tx.ExecContext(context, queryUpdate, verification.CredentialValue, dateTime, verification.UserId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return model.ErrUserAlreadyExists
+		}
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(model.ErrInternalDatabase, err.Error())
+	}
+
+	return nil
+}
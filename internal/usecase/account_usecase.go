@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+type AccountInterface interface {
+	DeleteAccount(ctx context.Context, request *model.UserDeleteRequest) (*model.UserDeleteResponse, error)
+	ExportAccount(ctx context.Context, id string) (*model.UserExportResponse, error)
+	PurgeDeletedAccounts(ctx context.Context) (int64, error)
+	StartAccountPurge(ctx context.Context, interval time.Duration)
+}
+
+func (u *useCase) DeleteAccount(ctx context.Context, request *model.UserDeleteRequest) (*model.UserDeleteResponse, error) {
+
+	_, err := u.verifyPassword(ctx, request.Id, request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedAt, err := u.AccountRepository.DeleteAccount(ctx, request.Id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserDeleteResponse{
+		DeletedAt: deletedAt,
+		PurgeAt:   deletedAt.Add(model.AccountDeletionGracePeriod),
+	}, nil
+}
+
+func (u *useCase) ExportAccount(ctx context.Context, id string) (*model.UserExportResponse, error) {
+	return u.AccountRepository.ExportAccount(ctx, id)
+}
+
+func (u *useCase) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	return u.AccountRepository.PurgeDeletedAccounts(ctx, time.Now().Add(-model.AccountDeletionGracePeriod))
+}
+
+// StartAccountPurge purges the accounts past their grace period every interval until ctx is done.
+func (u *useCase) StartAccountPurge(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := u.PurgeDeletedAccounts(ctx)
+			if err != nil {
+				u.Logger.Error().Err(err).Msg("Purge deleted accounts")
+			} else if purged > 0 {
+				u.Logger.Info().Int64("purged", purged).Msg("Purge deleted accounts")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
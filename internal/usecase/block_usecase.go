@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+type BlockInterface interface {
+	BlockUser(ctx context.Context, request model.BlockRequest) (*model.BlockResponse, error)
+	UnblockUser(ctx context.Context, request model.BlockRequest) error
+}
+
+func (u *useCase) BlockUser(ctx context.Context, request model.BlockRequest) (*model.BlockResponse, error) {
+
+	if request.Type == "" {
+		request.Type = model.BlockTypeBlock
+	}
+
+	// Check User Exists
+	_, _, err := u.FriendRepository.CheckAlreadyFriend(ctx, request.UserId, request.BlockedUserId)
+	if err != nil {
+		if errors.Is(err, model.ErrResNotFound.Error) {
+			return nil, model.ErrResNotFound.Error
+		}
+
+		return nil, err
+	}
+
+	// NOTE The repository also ends the friendship and drops pending requests of a block
+	return u.BlockRepository.Block(ctx, request)
+}
+
+func (u *useCase) UnblockUser(ctx context.Context, request model.BlockRequest) error {
+	return u.BlockRepository.Unblock(ctx, request.UserId, request.BlockedUserId)
+}
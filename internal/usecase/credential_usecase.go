@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+type CredentialInterface interface {
+	UserReplaceCredential(ctx context.Context, request *model.UserReplaceCredentialRequest) (*model.CredentialVerificationResponse, error)
+	UserRemoveCredential(ctx context.Context, request *model.UserRemoveCredentialRequest) error
+}
+
+// credentialOf returns the current value of credentialType on user.
+func credentialOf(user *model.User, credentialType model.UserCredentialType) sql.NullString {
+	if credentialType == model.Phone {
+		return user.Phone
+	}
+
+	return user.Email
+}
+
+// verifyPassword loads the user and checks password against it, throttling wrong passwords.
+func (u *useCase) verifyPassword(ctx context.Context, id uuid.UUID, password string) (*model.User, error) {
+
+	user, _, err := u.UserRepository.FindById(ctx, id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.checkPassword(ctx, user, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *useCase) UserReplaceCredential(ctx context.Context, request *model.UserReplaceCredentialRequest) (*model.CredentialVerificationResponse, error) {
+
+	user, err := u.verifyPassword(ctx, request.Id, request.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if current := credentialOf(user, request.CredentialType); current.Valid && current.String == request.CredentialValue {
+		return nil, model.ErrCredentialUnchanged
+	}
+
+	requestAuth := model.UserAuthRequest{
+		CredentialType:  request.CredentialType,
+		CredentialValue: request.CredentialValue,
+	}
+
+	var existing *model.User
+	if request.CredentialType == model.Email {
+		_, existing, err = u.UserRepository.FindByEmail(ctx, &requestAuth)
+	} else {
+		_, existing, err = u.UserRepository.FindByPhone(ctx, &requestAuth)
+	}
+
+	if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if existing != nil && existing.Id != uuid.Nil {
+		return nil, model.ErrUserAlreadyExists
+	}
+
+	// NOTE The current credential keeps working until the new one is confirmed
+	return u.startCredentialVerification(ctx, request.Id.String(), request.CredentialType, request.CredentialValue)
+}
+
+func (u *useCase) UserRemoveCredential(ctx context.Context, request *model.UserRemoveCredentialRequest) error {
+
+	user, err := u.verifyPassword(ctx, request.Id, request.Password)
+	if err != nil {
+		return err
+	}
+
+	if !credentialOf(user, request.CredentialType).Valid {
+		return model.ErrCredentialNotLinked
+	}
+
+	other := model.Email
+	if request.CredentialType == model.Email {
+		other = model.Phone
+	}
+
+	if !credentialOf(user, other).Valid {
+		return model.ErrLastCredential
+	}
+
+	requestUpdate := model.User{Id: request.Id}
+	if request.CredentialType == model.Email {
+		requestUpdate.Email = sql.NullString{Valid: true}
+	} else {
+		requestUpdate.Phone = sql.NullString{Valid: true}
+	}
+
+	_, err = u.UserRepository.UpdateUserData(ctx, requestUpdate)
+	return err
+}
@@ -9,12 +9,16 @@ import (
 
 type FriendInterface interface {
 	// IsFriend(ctx context.Context, userID string, friendID string) (bool, error)
-	AddFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error)
+	SendFriendRequest(ctx context.Context, userID string, friendID string) (*model.FriendRequestResponse, error)
+	GetFriendRequestList(ctx context.Context, request model.GetFriendRequestListRequest) (model.PaginateResponse[model.FriendRequestResponse], error)
+	AcceptFriendRequest(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error)
+	RejectFriendRequest(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error)
+	CancelFriendRequest(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error)
 	RemoveFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error)
 	GetFriendList(ctx context.Context, request model.GetFriendListRequest) (model.PaginateResponse[model.FriendResponse], error)
 }
 
-func (u *useCase) AddFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error) {
+func (u *useCase) SendFriendRequest(ctx context.Context, userID string, friendID string) (*model.FriendRequestResponse, error) {
 	// Check User Exists
 	_, exists, err := u.FriendRepository.CheckAlreadyFriend(ctx, userID, friendID)
 	if err != nil {
@@ -29,7 +33,26 @@ func (u *useCase) AddFriend(ctx context.Context, userID string, friendID string)
 		return nil, model.ErrAlreadyBeFriend
 	}
 
-	result, err := u.FriendRepository.AddFriend(ctx, model.FriendRequest{
+	blocked, err := u.BlockRepository.IsBlocked(ctx, userID, friendID)
+	if err != nil {
+		return nil, err
+	}
+
+	if blocked {
+		return nil, model.ErrBlocked
+	}
+
+	// NOTE The other user already asked us, sending back means accepting
+	incoming, err := u.FriendRepository.FindPendingFriendRequest(ctx, friendID, userID)
+	if err != nil && !errors.Is(err, model.ErrFriendRequestNotFound) {
+		return nil, err
+	}
+
+	if incoming != nil {
+		return u.FriendRepository.AcceptFriendRequest(ctx, incoming.Id)
+	}
+
+	result, err := u.FriendRepository.CreateFriendRequest(ctx, model.FriendRequest{
 		UserId:   userID,
 		FriendId: friendID,
 	})
@@ -40,6 +63,82 @@ func (u *useCase) AddFriend(ctx context.Context, userID string, friendID string)
 	return result, nil
 }
 
+func (u *useCase) GetFriendRequestList(ctx context.Context, request model.GetFriendRequestListRequest) (model.PaginateResponse[model.FriendRequestResponse], error) {
+
+	if request.Limit == 0 {
+		request.Limit = 5
+	}
+
+	if request.Type == "" {
+		request.Type = model.FriendRequestIncoming
+	}
+
+	result, meta, err := u.FriendRepository.FindAllFriendRequest(ctx, request)
+	if err != nil {
+		return model.PaginateResponse[model.FriendRequestResponse]{
+			Data: []model.FriendRequestResponse{},
+			Meta: model.MetaDataResponse{
+				Total:  0,
+				Limit:  request.Limit,
+				Offset: request.Offset,
+			},
+			Message: "Ok",
+		}, err
+	}
+
+	return model.PaginateResponse[model.FriendRequestResponse]{
+		Data:    result,
+		Meta:    meta,
+		Message: "Ok",
+	}, nil
+}
+
+func (u *useCase) AcceptFriendRequest(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error) {
+	friendRequest, err := u.FriendRepository.FindFriendRequestById(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Only the receiver can accept
+	if friendRequest.ReceiverId != request.UserId {
+		return nil, model.ErrFriendRequestNotFound
+	}
+
+	if friendRequest.Status != model.FriendRequestPending {
+		return nil, model.ErrFriendRequestNotPending
+	}
+
+	return u.FriendRepository.AcceptFriendRequest(ctx, friendRequest.Id)
+}
+
+func (u *useCase) RejectFriendRequest(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error) {
+	friendRequest, err := u.FriendRepository.FindFriendRequestById(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Only the receiver can reject
+	if friendRequest.ReceiverId != request.UserId {
+		return nil, model.ErrFriendRequestNotFound
+	}
+
+	return u.FriendRepository.UpdateFriendRequestStatus(ctx, friendRequest.Id, model.FriendRequestRejected)
+}
+
+func (u *useCase) CancelFriendRequest(ctx context.Context, request model.FriendRequestActionRequest) (*model.FriendRequestResponse, error) {
+	friendRequest, err := u.FriendRepository.FindFriendRequestById(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Only the sender can cancel
+	if friendRequest.SenderId != request.UserId {
+		return nil, model.ErrFriendRequestNotFound
+	}
+
+	return u.FriendRepository.UpdateFriendRequestStatus(ctx, friendRequest.Id, model.FriendRequestCancelled)
+}
+
 func (u *useCase) RemoveFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error) {
 	// Check User Exists
 	_, exists, err := u.FriendRepository.CheckAlreadyFriend(ctx, userID, friendID)
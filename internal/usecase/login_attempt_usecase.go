@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+// loginAttemptKey is one dimension failed logins are counted on.
+type loginAttemptKey struct {
+	Key      string
+	Throttle helper.LoginThrottle
+}
+
+// loginAttemptKeys returns the credential key first, then the ip address key when known.
+func loginAttemptKeys(request *model.UserLoginRequest) []loginAttemptKey {
+	keys := []loginAttemptKey{{
+		Key:      "credential:" + string(request.CredentialType) + ":" + strings.ToLower(request.CredentialValue),
+		Throttle: helper.CredentialLoginThrottle,
+	}}
+
+	if request.IpAddress != "" {
+		keys = append(keys, loginAttemptKey{Key: "ip:" + request.IpAddress, Throttle: helper.IpLoginThrottle})
+	}
+
+	return keys
+}
+
+// checkLoginAttempt returns a *model.LoginLockedError while any key still has to wait.
+func (u *useCase) checkLoginAttempt(ctx context.Context, keys []loginAttemptKey) error {
+
+	var retryAfter time.Duration
+	now := time.Now()
+
+	for _, key := range keys {
+		attempt, err := u.LoginAttemptRepository.FindLoginAttempt(ctx, key.Key)
+		if err != nil {
+			return err
+		}
+
+		if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &model.LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed login on every key and delays the next attempt.
+func (u *useCase) recordLoginFailure(ctx context.Context, keys []loginAttemptKey) error {
+
+	now := time.Now()
+
+	for _, key := range keys {
+		attempt, err := u.LoginAttemptRepository.RecordLoginFailure(ctx, key.Key, now, key.Throttle.ResetAfter)
+		if err != nil {
+			return err
+		}
+
+		delay := key.Throttle.Delay(attempt.Failures)
+		if delay == 0 {
+			continue
+		}
+
+		if attempt.Failures >= key.Throttle.LockoutAfter {
+			u.Logger.Warn().Str("key", key.Key).Int("failures", attempt.Failures).Msg("login locked out")
+		}
+
+		err = u.LoginAttemptRepository.LockLoginAttempt(ctx, key.Key, now.Add(delay))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkPassword compares password with the one of a logged in user. Wrong passwords are
+// throttled per user like failed logins, so a stolen access token cannot be used to guess it.
+func (u *useCase) checkPassword(ctx context.Context, user *model.User, password string) error {
+
+	attemptKeys := []loginAttemptKey{{Key: "password:" + user.Id.String(), Throttle: helper.CredentialLoginThrottle}}
+	err := u.checkLoginAttempt(ctx, attemptKeys)
+	if err != nil {
+		return err
+	}
+
+	err = helper.ComparePassword(user.Password, password)
+	if err != nil {
+		err = u.recordLoginFailure(ctx, attemptKeys)
+		if err != nil {
+			return err
+		}
+		return model.ErrPasswordNotMatch
+	}
+
+	return u.LoginAttemptRepository.ResetLoginAttempt(ctx, attemptKeys[0].Key)
+}
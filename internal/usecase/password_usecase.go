@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+type PasswordInterface interface {
+	UserChangePassword(ctx context.Context, request *model.UserChangePasswordRequest) error
+	UserForgotPassword(ctx context.Context, request *model.UserForgotPasswordRequest) error
+	UserResetPassword(ctx context.Context, request *model.UserResetPasswordRequest) error
+}
+
+func (u *useCase) UserChangePassword(ctx context.Context, request *model.UserChangePasswordRequest) error {
+
+	user, _, err := u.UserRepository.FindById(ctx, request.Id.String())
+	if err != nil {
+		return err
+	}
+
+	err = u.checkPassword(ctx, user, request.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := helper.HashPassword(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// NOTE Other devices have to log in again with the new password
+	return u.UserRepository.UpdatePassword(ctx, request.Id.String(), hashedPassword, request.SessionId)
+}
+
+// UserForgotPassword sends a reset token to the credential. Unknown credentials are ignored
+// so the response does not tell which accounts exist.
+func (u *useCase) UserForgotPassword(ctx context.Context, request *model.UserForgotPasswordRequest) error {
+
+	requestAuth := model.UserAuthRequest{
+		CredentialType:  request.CredentialType,
+		CredentialValue: request.CredentialValue,
+	}
+
+	var (
+		user *model.User
+		err  error
+	)
+
+	if request.CredentialType == model.Email {
+		_, user, err = u.UserRepository.FindByEmail(ctx, &requestAuth)
+	}
+
+	if request.CredentialType == model.Phone {
+		_, user, err = u.UserRepository.FindByPhone(ctx, &requestAuth)
+	}
+
+	if errors.Is(err, model.ErrUserNotFound) || (err == nil && user == nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, tokenHash, err := helper.GenerateSecretToken()
+	if err != nil {
+		return err
+	}
+
+	// NOTE A request within the resend interval is dropped silently like an unknown credential
+	err = u.PasswordResetRepository.CreatePasswordReset(ctx, user.Id.String(), tokenHash, time.Now().Add(model.PasswordResetTTL))
+	if errors.Is(err, model.ErrPasswordResetTooSoon) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = u.Notifier.Send(ctx, model.Notification{
+		Channel: request.CredentialType,
+		To:      request.CredentialValue,
+		Subject: "Reset password",
+		Body:    fmt.Sprintf("Use this token to reset your password: %s, it expires in %d minutes.", token, int(model.PasswordResetTTL.Minutes())),
+	})
+	if err != nil {
+		u.Logger.Error().Err(err).Str("userId", user.Id.String()).Msg("send password reset")
+	}
+
+	return nil
+}
+
+func (u *useCase) UserResetPassword(ctx context.Context, request *model.UserResetPasswordRequest) error {
+
+	reset, err := u.PasswordResetRepository.FindPasswordReset(ctx, helper.HashToken(request.Token))
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := helper.HashPassword(request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return u.PasswordResetRepository.ResetPassword(ctx, reset, hashedPassword)
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
 	"github.com/Dzikuri/openidea-segokuning/internal/model"
 )
 
@@ -10,10 +11,21 @@ type PostInterface interface {
 	PostCreate(ctx context.Context, request *model.CreatePostRequest) (*model.PostResponse, error)
 	PostCreateComment(ctx context.Context, request *model.CreatePostCommentRequest) (*model.PostCommentResponse, error)
 	PostList(ctx context.Context, request *model.PostListRequest) (model.PaginateResponse[model.PostListResponse], error)
+	PostDetail(ctx context.Context, id string, userId string) (*model.PostDetailResponse, error)
+	PostCommentList(ctx context.Context, request *model.PostCommentListRequest) (model.PaginateResponse[model.PostCommentUserResponse], error)
+	PostUpdateComment(ctx context.Context, request *model.UpdatePostCommentRequest) (*model.PostCommentResponse, error)
+	PostDeleteComment(ctx context.Context, id string, userId string) error
+	PostUpdate(ctx context.Context, request *model.UpdatePostRequest) (*model.PostResponse, error)
+	PostDelete(ctx context.Context, id string, userId string) error
+	PostHistory(ctx context.Context, id string, userId string) ([]model.PostHistoryResponse, error)
 }
 
 func (u *useCase) PostCreate(ctx context.Context, request *model.CreatePostRequest) (*model.PostResponse, error) {
 
+	if request.Visibility == "" {
+		request.Visibility = model.PostVisibilityPublic
+	}
+
 	res, err := u.PostRepository.CreatePost(ctx, request)
 
 	if err != nil {
@@ -26,12 +38,40 @@ func (u *useCase) PostCreate(ctx context.Context, request *model.CreatePostReque
 
 func (u *useCase) PostCreateComment(ctx context.Context, request *model.CreatePostCommentRequest) (*model.PostCommentResponse, error) {
 
-	// NOTE Check Post is Exists
-	_, err := u.PostRepository.FindPostById(ctx, request.PostId)
+	// NOTE Check Post is Exists and visible to the commenter
+	post, err := u.PostRepository.FindPostById(ctx, request.PostId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Users blocked by the author (or blocking the author) cannot comment
+	blocked, err := u.BlockRepository.IsBlocked(ctx, post.UserId, request.UserId)
 	if err != nil {
 		return nil, err
 	}
 
+	if blocked {
+		return nil, model.ErrBlocked
+	}
+
+	// NOTE Replies must target a live comment of the same post and stay within the depth limit
+	if request.ParentCommentId != "" {
+		parent, err := u.PostRepository.FindPostCommentById(ctx, request.ParentCommentId)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.PostId != post.Id || parent.IsDeleted {
+			return nil, model.ErrCommentNotFound
+		}
+
+		if parent.Depth+1 > model.MaxCommentDepth {
+			return nil, model.ErrCommentDepthExceeded
+		}
+
+		request.Depth = parent.Depth + 1
+	}
+
 	res, err := u.PostRepository.CreatePostComment(ctx, request)
 
 	if err != nil {
@@ -58,9 +98,232 @@ func (u *useCase) PostList(ctx context.Context, request *model.PostListRequest)
 		}, err
 	}
 
+	for i := range res {
+		res[i].Comments = buildCommentTree(res[i].Comments)
+	}
+
 	return model.PaginateResponse[model.PostListResponse]{
 		Data:    res,
 		Meta:    meta,
 		Message: "Ok",
 	}, err
 }
+
+func (u *useCase) PostDetail(ctx context.Context, id string, userId string) (*model.PostDetailResponse, error) {
+
+	if !helper.IsValidUUID(id) {
+		return nil, model.ErrResNotFound.Error
+	}
+
+	// NOTE Explore drops the friends-only feed filter, visibility and blocks still apply
+	posts, _, err := u.PostRepository.PostList(ctx, &model.PostListRequest{
+		UserId:  userId,
+		PostId:  id,
+		Limit:   1,
+		Explore: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(posts) == 0 {
+		return nil, model.ErrResNotFound.Error
+	}
+
+	comments, meta, err := u.PostRepository.PostCommentList(ctx, &model.PostCommentListRequest{
+		PostId: id,
+		UserId: userId,
+		Limit:  model.CommentPageLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	post := posts[0]
+	post.Comments = buildCommentTree(comments)
+
+	return &model.PostDetailResponse{
+		PostListResponse: post,
+		CommentMeta:      meta,
+	}, nil
+}
+
+// buildCommentTree nests comments, ordered by creation time, under their parent.
+// Replies whose parent is not part of the list, e.g. in a feed entry that only has the latest
+// comments, are nested under a placeholder for the parent placed where the first of them was.
+func buildCommentTree(comments []model.PostCommentUserResponse) []model.PostCommentUserResponse {
+	exists := make(map[string]bool, len(comments))
+	for _, comment := range comments {
+		exists[comment.Id] = true
+	}
+
+	roots := make([]model.PostCommentUserResponse, 0)
+	children := make(map[string][]model.PostCommentUserResponse)
+	for _, comment := range comments {
+		if comment.ParentCommentId == "" {
+			roots = append(roots, comment)
+			continue
+		}
+
+		if !exists[comment.ParentCommentId] {
+			exists[comment.ParentCommentId] = true
+			roots = append(roots, model.PostCommentUserResponse{
+				PostCommentResponse: model.PostCommentResponse{
+					Id:     comment.ParentCommentId,
+					PostId: comment.PostId,
+					Depth:  comment.Depth - 1,
+				},
+				Reactions:     map[model.ReactionType]int{},
+				IsPlaceholder: true,
+			})
+		}
+
+		children[comment.ParentCommentId] = append(children[comment.ParentCommentId], comment)
+	}
+
+	var attach func(comment model.PostCommentUserResponse) model.PostCommentUserResponse
+	attach = func(comment model.PostCommentUserResponse) model.PostCommentUserResponse {
+		comment.Replies = make([]model.PostCommentUserResponse, 0, len(children[comment.Id]))
+		for _, reply := range children[comment.Id] {
+			comment.Replies = append(comment.Replies, attach(reply))
+		}
+		return comment
+	}
+
+	for i := range roots {
+		roots[i] = attach(roots[i])
+	}
+
+	return roots
+}
+
+func (u *useCase) PostCommentList(ctx context.Context, request *model.PostCommentListRequest) (model.PaginateResponse[model.PostCommentUserResponse], error) {
+
+	if request.Limit == 0 {
+		request.Limit = model.CommentPageLimit
+	}
+
+	// NOTE Check Post is Exists and visible to the caller
+	post, err := u.PostRepository.FindPostById(ctx, request.PostId, request.UserId)
+	if err != nil {
+		return model.PaginateResponse[model.PostCommentUserResponse]{}, err
+	}
+
+	// NOTE The feed hides posts across a block, so their comments are hidden too
+	blocked, err := u.BlockRepository.IsBlocked(ctx, post.UserId, request.UserId)
+	if err != nil {
+		return model.PaginateResponse[model.PostCommentUserResponse]{}, err
+	}
+
+	if blocked {
+		return model.PaginateResponse[model.PostCommentUserResponse]{}, model.ErrBlocked
+	}
+
+	res, meta, err := u.PostRepository.PostCommentList(ctx, request)
+	if err != nil {
+		return model.PaginateResponse[model.PostCommentUserResponse]{
+			Data: []model.PostCommentUserResponse{},
+			Meta: model.MetaDataResponse{
+				Total: 0,
+				Limit: request.Limit,
+			},
+			Message: "Ok",
+		}, err
+	}
+
+	return model.PaginateResponse[model.PostCommentUserResponse]{
+		Data:    buildCommentTree(res),
+		Meta:    meta,
+		Message: "Ok",
+	}, nil
+}
+
+func (u *useCase) PostUpdateComment(ctx context.Context, request *model.UpdatePostCommentRequest) (*model.PostCommentResponse, error) {
+
+	comment, err := u.PostRepository.FindPostCommentById(ctx, request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IsDeleted {
+		return nil, model.ErrCommentNotFound
+	}
+
+	// NOTE The parent post must still be visible to the caller
+	_, err = u.PostRepository.FindPostById(ctx, comment.PostId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE Only the author can edit a comment
+	if comment.UserId != request.UserId {
+		return nil, model.ErrForbidden
+	}
+
+	return u.PostRepository.UpdatePostComment(ctx, request)
+}
+
+func (u *useCase) PostDeleteComment(ctx context.Context, id string, userId string) error {
+
+	comment, err := u.PostRepository.FindPostCommentById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comment.IsDeleted {
+		return model.ErrCommentNotFound
+	}
+
+	post, err := u.PostRepository.FindPostById(ctx, comment.PostId, userId)
+	if err != nil {
+		return err
+	}
+
+	// NOTE The comment author and the owner of the post can remove a comment
+	if comment.UserId != userId && post.UserId != userId {
+		return model.ErrForbidden
+	}
+
+	return u.PostRepository.DeletePostComment(ctx, id)
+}
+
+func (u *useCase) PostUpdate(ctx context.Context, request *model.UpdatePostRequest) (*model.PostResponse, error) {
+
+	// NOTE Check Post is Exists and owned by the caller
+	post, err := u.PostRepository.FindPostById(ctx, request.Id, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.UserId != request.UserId {
+		return nil, model.ErrForbidden
+	}
+
+	return u.PostRepository.UpdatePost(ctx, request)
+}
+
+func (u *useCase) PostDelete(ctx context.Context, id string, userId string) error {
+
+	// NOTE Check Post is Exists and owned by the caller
+	post, err := u.PostRepository.FindPostById(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if post.UserId != userId {
+		return model.ErrForbidden
+	}
+
+	return u.PostRepository.DeletePost(ctx, id, userId)
+}
+
+func (u *useCase) PostHistory(ctx context.Context, id string, userId string) ([]model.PostHistoryResponse, error) {
+
+	// NOTE Anyone who can see the post can see its previous versions
+	_, err := u.PostRepository.FindPostById(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.PostRepository.PostHistory(ctx, id)
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+type ReactionInterface interface {
+	React(ctx context.Context, request model.ReactionRequest) (*model.ReactionResponse, error)
+	RemoveReaction(ctx context.Context, request model.ReactionRequest) error
+}
+
+func (u *useCase) React(ctx context.Context, request model.ReactionRequest) (*model.ReactionResponse, error) {
+
+	postId := request.PostId
+	authors := []string{}
+
+	// NOTE Reactions on removed comments are not allowed
+	if request.CommentId != "" {
+		comment, err := u.PostRepository.FindPostCommentById(ctx, request.CommentId)
+		if err != nil {
+			return nil, err
+		}
+
+		if comment.IsDeleted {
+			return nil, model.ErrCommentNotFound
+		}
+
+		postId = comment.PostId
+		authors = append(authors, comment.UserId)
+	}
+
+	// NOTE Check Post is Exists and visible to the reacting user
+	post, err := u.PostRepository.FindPostById(ctx, postId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	authors = append(authors, post.UserId)
+
+	for _, author := range authors {
+		blocked, err := u.BlockRepository.IsBlocked(ctx, author, request.UserId)
+		if err != nil {
+			return nil, err
+		}
+
+		if blocked {
+			return nil, model.ErrBlocked
+		}
+	}
+
+	return u.ReactionRepository.React(ctx, request)
+}
+
+func (u *useCase) RemoveReaction(ctx context.Context, request model.ReactionRequest) error {
+	return u.ReactionRepository.RemoveReaction(ctx, request)
+}
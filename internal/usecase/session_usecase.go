@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+type SessionInterface interface {
+	RefreshToken(ctx context.Context, request *model.RefreshTokenRequest) (*model.UserAuthResponse, error)
+	Logout(ctx context.Context, sessionId string, userId string) error
+	IsSessionActive(ctx context.Context, sessionId string, userId string) (bool, error)
+	TouchSession(ctx context.Context, sessionId string) error
+	GetSessionList(ctx context.Context, userId string, currentSessionId string) ([]model.SessionResponse, error)
+	RevokeSession(ctx context.Context, sessionId string, userId string) error
+}
+
+// startSession opens a session for user on the given device and returns its access and refresh tokens.
+func (u *useCase) startSession(ctx context.Context, user *model.User, userAgent string, ipAddress string) (string, string, error) {
+
+	refreshToken, refreshTokenHash, err := helper.GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	session, err := u.SessionRepository.CreateSession(ctx, model.Session{
+		UserId:    user.Id.String(),
+		UserAgent: userAgent,
+		IpAddress: ipAddress,
+	}, refreshTokenHash, time.Now().Add(helper.RefreshTokenExpireAt()))
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := helper.JwtGenerateToken(user, session.Id)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+func (u *useCase) RefreshToken(ctx context.Context, request *model.RefreshTokenRequest) (*model.UserAuthResponse, error) {
+
+	token, err := u.SessionRepository.FindRefreshToken(ctx, helper.HashToken(request.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if token.SessionRevoked {
+		return nil, model.ErrSessionRevoked
+	}
+
+	if token.ExpiresAt.Before(time.Now()) {
+		return nil, model.ErrInvalidRefreshToken
+	}
+
+	refreshToken, refreshTokenHash, err := helper.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE A refresh token is single use, presenting it again means it leaked: the whole session is revoked
+	if !token.UsedAt.Valid {
+		err = u.SessionRepository.RotateRefreshToken(ctx, token, refreshTokenHash, time.Now().Add(helper.RefreshTokenExpireAt()))
+	} else {
+		err = model.ErrRefreshTokenReused
+	}
+
+	if errors.Is(err, model.ErrRefreshTokenReused) {
+		u.Logger.Warn().Str("sessionId", token.SessionId).Msg("refresh token reused, revoking session")
+
+		errRevoke := u.SessionRepository.RevokeSession(ctx, token.SessionId, token.UserId)
+		if errRevoke != nil && !errors.Is(errRevoke, model.ErrSessionNotFound) {
+			return nil, errRevoke
+		}
+
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	user, _, err := u.UserRepository.FindById(ctx, token.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := helper.JwtGenerateToken(user, token.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserAuthResponse{
+		Phone:        user.Phone.String,
+		Email:        user.Email.String,
+		Name:         user.Name,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// Logout revokes the current session. A session revoked in the meantime counts as logged out.
+func (u *useCase) Logout(ctx context.Context, sessionId string, userId string) error {
+
+	err := u.SessionRepository.RevokeSession(ctx, sessionId, userId)
+	if errors.Is(err, model.ErrSessionNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (u *useCase) IsSessionActive(ctx context.Context, sessionId string, userId string) (bool, error) {
+	return u.SessionRepository.IsSessionActive(ctx, sessionId, userId)
+}
+
+func (u *useCase) TouchSession(ctx context.Context, sessionId string) error {
+	return u.SessionRepository.TouchSession(ctx, sessionId)
+}
+
+func (u *useCase) GetSessionList(ctx context.Context, userId string, currentSessionId string) ([]model.SessionResponse, error) {
+
+	sessions, err := u.SessionRepository.FindAllSession(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		lastSeenAt := session.CreatedAt
+		if session.LastSeenAt.Valid {
+			lastSeenAt = session.LastSeenAt.Time
+		}
+
+		res = append(res, model.SessionResponse{
+			Id:         session.Id,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: lastSeenAt,
+			Current:    session.Id == currentSessionId,
+		})
+	}
+
+	return res, nil
+}
+
+func (u *useCase) RevokeSession(ctx context.Context, sessionId string, userId string) error {
+	return u.SessionRepository.RevokeSession(ctx, sessionId, userId)
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"github.com/Dzikuri/openidea-segokuning/internal/notifier"
 	"github.com/Dzikuri/openidea-segokuning/internal/repository"
 	"github.com/rs/zerolog"
 )
@@ -9,20 +10,42 @@ type UseCase interface {
 	UserInterface
 	FriendInterface
 	PostInterface
+	BlockInterface
+	ReactionInterface
+	SessionInterface
+	PasswordInterface
+	CredentialInterface
+	AccountInterface
 }
 
 type useCase struct {
-	Logger           zerolog.Logger
-	UserRepository   repository.RepositoryUser
-	FriendRepository repository.RepositoryFriend
-	PostRepository   repository.RepositoryPost
+	Logger                  zerolog.Logger
+	UserRepository          repository.RepositoryUser
+	FriendRepository        repository.RepositoryFriend
+	PostRepository          repository.RepositoryPost
+	BlockRepository         repository.RepositoryBlock
+	ReactionRepository      repository.RepositoryReaction
+	SessionRepository       repository.RepositorySession
+	LoginAttemptRepository  repository.RepositoryLoginAttempt
+	VerificationRepository  repository.RepositoryVerification
+	PasswordResetRepository repository.RepositoryPasswordReset
+	AccountRepository       repository.RepositoryAccount
+	Notifier                notifier.Notifier
 }
 
-func NewUseCase(logger zerolog.Logger, userRepository repository.RepositoryUser, friendRepository repository.RepositoryFriend, postRepository repository.RepositoryPost) UseCase {
+func NewUseCase(logger zerolog.Logger, userRepository repository.RepositoryUser, friendRepository repository.RepositoryFriend, postRepository repository.RepositoryPost, blockRepository repository.RepositoryBlock, reactionRepository repository.RepositoryReaction, sessionRepository repository.RepositorySession, loginAttemptRepository repository.RepositoryLoginAttempt, verificationRepository repository.RepositoryVerification, passwordResetRepository repository.RepositoryPasswordReset, accountRepository repository.RepositoryAccount, notifier notifier.Notifier) UseCase {
 	return &useCase{
-		Logger:           logger,
-		UserRepository:   userRepository,
-		FriendRepository: friendRepository,
-		PostRepository:   postRepository,
+		Logger:                  logger,
+		UserRepository:          userRepository,
+		FriendRepository:        friendRepository,
+		PostRepository:          postRepository,
+		BlockRepository:         blockRepository,
+		ReactionRepository:      reactionRepository,
+		SessionRepository:       sessionRepository,
+		LoginAttemptRepository:  loginAttemptRepository,
+		VerificationRepository:  verificationRepository,
+		PasswordResetRepository: passwordResetRepository,
+		AccountRepository:       accountRepository,
+		Notifier:                notifier,
 	}
 }
@@ -2,7 +2,7 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 
 	"github.com/Dzikuri/openidea-segokuning/internal/helper"
 	"github.com/Dzikuri/openidea-segokuning/internal/model"
@@ -13,9 +13,11 @@ type UserInterface interface {
 	UserRegister(ctx context.Context, request *model.UserAuthRequest) (*model.UserAuthResponse, error)
 	UserLogin(ctx context.Context, request *model.UserLoginRequest) (*model.UserAuthResponse, error)
 	GetUserByID(ctx context.Context, id string) (*model.UserResponse, int, error)
-	UserLinkEmail(ctx context.Context, request *model.UserLinkEmailRequest) (*model.UserResponse, error)
-	UserLinkPhone(ctx context.Context, request *model.UserLinkPhoneRequest) (*model.UserResponse, error)
+	UserLinkEmail(ctx context.Context, request *model.UserLinkEmailRequest) (*model.CredentialVerificationResponse, error)
+	UserLinkPhone(ctx context.Context, request *model.UserLinkPhoneRequest) (*model.CredentialVerificationResponse, error)
+	UserLinkConfirm(ctx context.Context, request *model.UserLinkConfirmRequest) error
 	UserUpdateAccount(ctx context.Context, request *model.UserUpdateAccount) (*model.UserResponse, error)
+	UserProfile(ctx context.Context, id string, viewerId string) (*model.UserProfileResponse, error)
 }
 
 func (u *useCase) UserRegister(ctx context.Context, request *model.UserAuthRequest) (*model.UserAuthResponse, error) {
@@ -36,7 +38,7 @@ func (u *useCase) UserRegister(ctx context.Context, request *model.UserAuthReque
 	}
 
 	// Generate JWT
-	token, err := helper.JwtGenerateToken(result)
+	token, refreshToken, err := u.startSession(ctx, result, request.UserAgent, request.IpAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -44,10 +46,11 @@ func (u *useCase) UserRegister(ctx context.Context, request *model.UserAuthReque
 	// return response
 
 	return &model.UserAuthResponse{
-		Phone:       result.Phone.String,
-		Email:       result.Email.String,
-		Name:        result.Name,
-		AccessToken: token,
+		Phone:        result.Phone.String,
+		Email:        result.Email.String,
+		Name:         result.Name,
+		AccessToken:  token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 func (u *useCase) UserLogin(ctx context.Context, request *model.UserLoginRequest) (*model.UserAuthResponse, error) {
@@ -60,10 +63,17 @@ func (u *useCase) UserLogin(ctx context.Context, request *model.UserLoginRequest
 
 	var (
 		exists bool
-		result *model.UserResponse
+		result *model.User
 		err    error
 	)
 
+	// NOTE Failed logins are throttled per credential and per ip address
+	attemptKeys := loginAttemptKeys(request)
+	err = u.checkLoginAttempt(ctx, attemptKeys)
+	if err != nil {
+		return nil, err
+	}
+
 	if requestAuth.CredentialType == model.Email {
 
 		exists, result, err = u.UserRepository.FindByEmail(ctx, &requestAuth)
@@ -73,30 +83,47 @@ func (u *useCase) UserLogin(ctx context.Context, request *model.UserLoginRequest
 		exists, result, err = u.UserRepository.FindByPhone(ctx, &requestAuth)
 	}
 
+	if errors.Is(err, model.ErrUserNotFound) || (err == nil && !exists) {
+		err = u.recordLoginFailure(ctx, attemptKeys)
+		if err != nil {
+			return nil, err
+		}
+		return nil, model.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, model.ErrUserNotFound
-	}
 
 	err = helper.ComparePassword(result.Password, request.Password)
 
 	if err != nil {
+		err = u.recordLoginFailure(ctx, attemptKeys)
+		if err != nil {
+			return nil, err
+		}
 		return nil, model.ErrPasswordNotMatch
 	}
 
+	// NOTE Only the credential key is reset. Resetting the ip key too would let anyone holding one
+	// valid account clear the throttle of their address between guesses against other accounts,
+	// the ip key decays on its own once IpLoginThrottle.ResetAfter passes without a failure.
+	err = u.LoginAttemptRepository.ResetLoginAttempt(ctx, attemptKeys[0].Key)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate JWT
-	token, err := helper.JwtGenerateToken(result)
+	token, refreshToken, err := u.startSession(ctx, result, request.UserAgent, request.IpAddress)
 	if err != nil {
 		return nil, err
 	}
 	// return response
 	return &model.UserAuthResponse{
-		Phone:       result.Phone.String,
-		Email:       result.Email.String,
-		Name:        result.Name,
-		AccessToken: token,
+		Phone:        result.Phone.String,
+		Email:        result.Email.String,
+		Name:         result.Name,
+		AccessToken:  token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
@@ -105,17 +132,10 @@ func (u *useCase) GetUserByID(ctx context.Context, id string) (*model.UserRespon
 	if err != nil {
 		return nil, code, err
 	}
-	return &model.UserResponse{
-		Id:        user.Id,
-		Email:     user.Email,
-		Phone:     user.Phone,
-		Name:      user.Name,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}, code, nil
+	return model.NewUserResponse(user), code, nil
 }
 
-func (u *useCase) UserLinkEmail(ctx context.Context, request *model.UserLinkEmailRequest) (*model.UserResponse, error) {
+func (u *useCase) UserLinkEmail(ctx context.Context, request *model.UserLinkEmailRequest) (*model.CredentialVerificationResponse, error) {
 	// Find By Id
 	resUserId, _, err := u.UserRepository.FindById(ctx, request.Id.String())
 	if err != nil {
@@ -142,20 +162,11 @@ func (u *useCase) UserLinkEmail(ctx context.Context, request *model.UserLinkEmai
 
 	}
 
-	requestUpdate := new(model.UserResponse)
-
-	requestUpdate.Id = request.Id
-	requestUpdate.Email = sql.NullString{request.Email, true}
-
-	_, err = u.UserRepository.UpdateUserData(ctx, *requestUpdate)
-	if condition := err != nil; condition {
-		return nil, err
-	}
-
-	return nil, nil
+	// NOTE The email is only written to the user once the code sent to it is confirmed
+	return u.startCredentialVerification(ctx, request.Id.String(), model.Email, request.Email)
 }
 
-func (u *useCase) UserLinkPhone(ctx context.Context, request *model.UserLinkPhoneRequest) (*model.UserResponse, error) {
+func (u *useCase) UserLinkPhone(ctx context.Context, request *model.UserLinkPhoneRequest) (*model.CredentialVerificationResponse, error) {
 
 	// Find By Id
 	resUserId, _, err := u.UserRepository.FindById(ctx, request.Id.String())
@@ -184,24 +195,13 @@ func (u *useCase) UserLinkPhone(ctx context.Context, request *model.UserLinkPhon
 
 	}
 
-	requestUpdate := new(model.UserResponse)
-
-	requestUpdate.Id = request.Id
-	requestUpdate.Phone = sql.NullString{request.Phone, true}
-
-	_, err = u.UserRepository.UpdateUserData(ctx, *requestUpdate)
-	if condition := err != nil; condition {
-		return nil, err
-	}
-
-	return &model.UserResponse{
-		Phone: requestUpdate.Phone,
-	}, nil
+	// NOTE The phone is only written to the user once the code sent to it is confirmed
+	return u.startCredentialVerification(ctx, request.Id.String(), model.Phone, request.Phone)
 }
 
 func (u *useCase) UserUpdateAccount(ctx context.Context, request *model.UserUpdateAccount) (*model.UserResponse, error) {
 
-	requestUpdate := new(model.UserResponse)
+	requestUpdate := new(model.User)
 
 	requestUpdate.Id = request.Id
 	requestUpdate.Name = request.Name
@@ -214,3 +214,16 @@ func (u *useCase) UserUpdateAccount(ctx context.Context, request *model.UserUpda
 
 	return nil, nil
 }
+
+func (u *useCase) UserProfile(ctx context.Context, id string, viewerId string) (*model.UserProfileResponse, error) {
+
+	profile, err := u.UserRepository.FindProfile(ctx, id, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Relation.Self = profile.Id == viewerId
+	profile.FriendshipStatus = profile.Relation.Status()
+
+	return profile, nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+// startCredentialVerification stores credentialValue as pending for userId and sends it a one-time code.
+func (u *useCase) startCredentialVerification(ctx context.Context, userId string, credentialType model.UserCredentialType, credentialValue string) (*model.CredentialVerificationResponse, error) {
+
+	code, err := helper.GenerateVerificationCode(6)
+	if err != nil {
+		return nil, err
+	}
+
+	verification, err := u.VerificationRepository.CreateCredentialVerification(ctx, model.CredentialVerification{
+		UserId:          userId,
+		CredentialType:  credentialType,
+		CredentialValue: credentialValue,
+		CodeHash:        helper.HashToken(code),
+		ExpiresAt:       time.Now().Add(model.VerificationCodeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.Notifier.Send(ctx, model.Notification{
+		Channel: credentialType,
+		To:      credentialValue,
+		Subject: "Verification code",
+		Body:    fmt.Sprintf("Your verification code is %s, it expires in %d minutes.", code, int(model.VerificationCodeTTL.Minutes())),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CredentialVerificationResponse{
+		CredentialType:  verification.CredentialType,
+		CredentialValue: verification.CredentialValue,
+		ExpiresAt:       verification.ExpiresAt,
+	}, nil
+}
+
+func (u *useCase) UserLinkConfirm(ctx context.Context, request *model.UserLinkConfirmRequest) error {
+
+	// NOTE The attempt is counted before the code is compared, whatever the outcome
+	verification, err := u.VerificationRepository.ClaimVerificationAttempt(ctx, request.Id.String(), request.CredentialType)
+	if err != nil {
+		return err
+	}
+
+	if verification.ExpiresAt.Before(time.Now()) {
+		return model.ErrVerificationExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(helper.HashToken(request.Code)), []byte(verification.CodeHash)) != 1 {
+		return model.ErrVerificationCodeInvalid
+	}
+
+	return u.VerificationRepository.ConfirmCredentialVerification(ctx, verification)
+}
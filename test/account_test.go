@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+)
+
+func TestFriendCountDecrements(t *testing.T) {
+	// friends rows go both ways, as AcceptFriendRequest inserts them
+	friendships := [][2]string{
+		{"alice", "purged-1"}, {"purged-1", "alice"},
+		{"alice", "purged-2"}, {"purged-2", "alice"},
+		{"bob", "purged-1"}, {"purged-1", "bob"},
+		{"purged-1", "purged-2"}, {"purged-2", "purged-1"},
+	}
+
+	got := repository.FriendCountDecrements([]string{"purged-1", "purged-2"}, friendships)
+	want := map[string]int{"alice": 2, "bob": 1}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FriendCountDecrements = %v, want %v", got, want)
+	}
+}
+
+func TestFriendCountDecrementsWithoutFriends(t *testing.T) {
+	got := repository.FriendCountDecrements([]string{"purged-1"}, nil)
+
+	if len(got) != 0 {
+		t.Errorf("FriendCountDecrements = %v, want no decrements", got)
+	}
+}
+
+func TestDeleteAccountThrottlesWrongPasswords(t *testing.T) {
+	user := passwordUser(t, "secret1")
+	uc := newTestUseCase(testRepositories{User: newFakeUserRepository(newFakeSessions(), user)})
+
+	request := &model.UserDeleteRequest{Id: user.Id, Password: "wrong"}
+	for i := 0; i <= helper.CredentialLoginThrottle.FreeAttempts; i++ {
+		_, err := uc.DeleteAccount(context.Background(), request)
+		if !errors.Is(err, model.ErrPasswordNotMatch) {
+			t.Fatalf("attempt %d: err = %v, want %v", i+1, err, model.ErrPasswordNotMatch)
+		}
+	}
+
+	// NOTE The account repository is never reached while locked
+	request.Password = "secret1"
+	_, err := uc.DeleteAccount(context.Background(), request)
+
+	var locked *model.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want *model.LoginLockedError", err)
+	}
+}
+
+func TestReparentCommentsKeepsOtherUsersReplies(t *testing.T) {
+	// a(alice) -> b(purged) -> c(bob) -> d(purged) -> e(carol), f(purged) -> g(bob), h(dave) untouched
+	comments := []model.CommentNode{
+		{Id: "a", UserId: "alice", Depth: 0},
+		{Id: "b", ParentCommentId: "a", UserId: "purged", Depth: 1},
+		{Id: "c", ParentCommentId: "b", UserId: "bob", Depth: 2},
+		{Id: "d", ParentCommentId: "c", UserId: "purged", Depth: 3},
+		{Id: "e", ParentCommentId: "d", UserId: "carol", Depth: 4},
+		{Id: "f", UserId: "purged", Depth: 0},
+		{Id: "g", ParentCommentId: "f", UserId: "bob", Depth: 1},
+		{Id: "h", ParentCommentId: "a", UserId: "dave", Depth: 1},
+	}
+
+	got := model.ReparentComments([]string{"purged"}, comments)
+	want := []model.CommentNode{
+		{Id: "c", ParentCommentId: "a", UserId: "bob", Depth: 1},
+		{Id: "e", ParentCommentId: "c", UserId: "carol", Depth: 2},
+		{Id: "g", UserId: "bob", Depth: 0},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReparentComments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReparentCommentsWithoutPurgedComments(t *testing.T) {
+	comments := []model.CommentNode{
+		{Id: "a", UserId: "alice"},
+		{Id: "b", ParentCommentId: "a", UserId: "bob", Depth: 1},
+	}
+
+	if got := model.ReparentComments([]string{"purged"}, comments); len(got) != 0 {
+		t.Errorf("ReparentComments() = %+v, want no changes", got)
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+func TestBlockUserDefaultsToBlock(t *testing.T) {
+	blocks := newFakeBlockRepository()
+	useCase := newTestUseCase(testRepositories{Friend: newFakeFriendRepository("alice", "bob"), Block: blocks})
+
+	result, err := useCase.BlockUser(context.Background(), model.BlockRequest{UserId: "alice", BlockedUserId: "bob"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Type != model.BlockTypeBlock {
+		t.Errorf("type = %q, want %q", result.Type, model.BlockTypeBlock)
+	}
+}
+
+func TestBlockUnknownUser(t *testing.T) {
+	blocks := newFakeBlockRepository()
+	useCase := newTestUseCase(testRepositories{Friend: newFakeFriendRepository("alice"), Block: blocks})
+
+	_, err := useCase.BlockUser(context.Background(), model.BlockRequest{UserId: "alice", BlockedUserId: "ghost"})
+	if !errors.Is(err, model.ErrResNotFound.Error) {
+		t.Errorf("err = %v, want %v", err, model.ErrResNotFound.Error)
+	}
+
+	if len(blocks.Blocks) != 0 {
+		t.Errorf("blocks = %v, want none", blocks.Blocks)
+	}
+}
+
+func TestBlockAndMuteOnFriendRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		blockType model.BlockType
+		blocker   string
+		wantErr   error
+	}{
+		{"sender blocked receiver", model.BlockTypeBlock, "alice", model.ErrBlocked},
+		{"receiver blocked sender", model.BlockTypeBlock, "bob", model.ErrBlocked},
+		{"sender muted receiver", model.BlockTypeMute, "alice", nil},
+		{"receiver muted sender", model.BlockTypeMute, "bob", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := newTestUseCase(testRepositories{Friend: newFakeFriendRepository("alice", "bob"), Block: newFakeBlockRepository()})
+
+			other := "bob"
+			if tt.blocker == "bob" {
+				other = "alice"
+			}
+
+			_, err := useCase.BlockUser(context.Background(), model.BlockRequest{UserId: tt.blocker, BlockedUserId: other, Type: tt.blockType})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = useCase.SendFriendRequest(context.Background(), "alice", "bob")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SendFriendRequest err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnblockUser(t *testing.T) {
+	useCase := newTestUseCase(testRepositories{Friend: newFakeFriendRepository("alice", "bob"), Block: newFakeBlockRepository()})
+
+	err := useCase.UnblockUser(context.Background(), model.BlockRequest{UserId: "alice", BlockedUserId: "bob"})
+	if !errors.Is(err, model.ErrNotBlocked) {
+		t.Errorf("unblock without block: err = %v, want %v", err, model.ErrNotBlocked)
+	}
+
+	if _, err := useCase.BlockUser(context.Background(), model.BlockRequest{UserId: "alice", BlockedUserId: "bob"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := useCase.UnblockUser(context.Background(), model.BlockRequest{UserId: "alice", BlockedUserId: "bob"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := useCase.SendFriendRequest(context.Background(), "alice", "bob"); err != nil {
+		t.Errorf("friend request after unblock: err = %v", err)
+	}
+}
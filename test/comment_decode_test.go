@@ -0,0 +1,122 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+)
+
+// aggregatedComment renders a comment the way json_build_object does in PostRepository.PostList.
+func aggregatedComment(t *testing.T, id string, comment string, createdAt string, creatorName string) string {
+	text, err := json.Marshal(comment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := json.Marshal(creatorName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fmt.Sprintf(`{"id" : "%s", "postId" : "7c3c5b52-6f0a-4a4b-9d55-3a7d2e8e0a01", "userId" : "0b6f2c1e-8d4a-4f5e-9a3b-1c2d3e4f5a6b", "parentCommentId" : null, "depth" : 0, "comment" : %s, "isDeleted" : false, "createdAt" : "%s", "updatedAt" : "%s", "creator" : {"userId" : "0b6f2c1e-8d4a-4f5e-9a3b-1c2d3e4f5a6b", "name" : %s, "imageUrl" : "https://example.com/a,b.jpg", "friendCount" : 12, "createdAt" : "2024-01-02T03:04:05.678901+00:00"}}`,
+		id, text, createdAt, createdAt, name)
+}
+
+func TestDecodeCommentsKeepsSpecialCharacters(t *testing.T) {
+	comments := []string{
+		"hello, world, with, commas",
+		`she said "hi" and 'bye'`,
+		"line one\nline two\r\n\tindented",
+		"unicode 日本語 emoji 🎉 ümlaut",
+		`back\slash and {braces} [brackets]`,
+		"",
+	}
+
+	data := "["
+	for i, comment := range comments {
+		if i > 0 {
+			data += ", "
+		}
+		data += aggregatedComment(t, fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i), comment, "2024-03-20T10:15:30.123456+07:00", "Nama, \"Pengguna\"")
+	}
+	data += "]"
+
+	decoded, err := repository.DecodeComments([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decoded) != len(comments) {
+		t.Fatalf("decoded %d comments, want %d", len(decoded), len(comments))
+	}
+
+	for i, comment := range comments {
+		if decoded[i].Comment != comment {
+			t.Errorf("comment %d = %q, want %q", i, decoded[i].Comment, comment)
+		}
+
+		if decoded[i].Creator.Name != "Nama, \"Pengguna\"" {
+			t.Errorf("creator name %d = %q", i, decoded[i].Creator.Name)
+		}
+
+		if decoded[i].Creator.ImageUrl != "https://example.com/a,b.jpg" {
+			t.Errorf("creator image %d = %q", i, decoded[i].Creator.ImageUrl)
+		}
+
+		if decoded[i].Creator.FriendCount != 12 {
+			t.Errorf("creator friend count %d = %d", i, decoded[i].Creator.FriendCount)
+		}
+	}
+}
+
+func TestDecodeCommentsKeepsTimeZone(t *testing.T) {
+	data := "[" + aggregatedComment(t, "00000000-0000-0000-0000-000000000001", "ok", "2024-03-20T10:15:30.123456+07:00", "name") + "]"
+
+	decoded, err := repository.DecodeComments([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := time.Date(2024, 3, 20, 3, 15, 30, 123456000, time.UTC)
+	if !decoded[0].CreatedAt.Equal(expected) {
+		t.Errorf("created at = %v, want %v", decoded[0].CreatedAt, expected)
+	}
+
+	if _, offset := decoded[0].CreatedAt.Zone(); offset != 7*60*60 {
+		t.Errorf("offset = %d, want %d", offset, 7*60*60)
+	}
+}
+
+func TestDecodeCommentsMasksDeletedAndNestsFields(t *testing.T) {
+	data := `[{"id" : "00000000-0000-0000-0000-000000000002", "postId" : "p", "userId" : "u", "parentCommentId" : "00000000-0000-0000-0000-000000000001", "depth" : 1, "comment" : "", "isDeleted" : true, "createdAt" : "2024-03-20T10:15:30+00:00", "updatedAt" : "2024-03-20T10:15:30+00:00", "creator" : {"userId" : "u", "name" : "n", "imageUrl" : "", "friendCount" : 0, "createdAt" : "2024-03-20T10:15:30+00:00"}}]`
+
+	decoded, err := repository.DecodeComments([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded[0].Comment != model.CommentRemovedMessage || !decoded[0].IsDeleted {
+		t.Errorf("deleted comment = %q (isDeleted %v)", decoded[0].Comment, decoded[0].IsDeleted)
+	}
+
+	if decoded[0].ParentCommentId != "00000000-0000-0000-0000-000000000001" || decoded[0].Depth != 1 {
+		t.Errorf("parent = %q depth = %d", decoded[0].ParentCommentId, decoded[0].Depth)
+	}
+}
+
+func TestDecodeCommentsEmpty(t *testing.T) {
+	for _, data := range []string{"", "[]"} {
+		decoded, err := repository.DecodeComments([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", data, err)
+		}
+
+		if decoded == nil || len(decoded) != 0 {
+			t.Errorf("decoded %q = %#v, want empty slice", data, decoded)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/usecase"
+)
+
+// newBobComment returns bob's comment on alice's post, carol can see the post and dave cannot.
+func newBobComment() (*fakePostRepository, usecase.UseCase) {
+	posts := newFakePostRepository(&model.PostResponse{Id: "post", UserId: "alice"})
+	posts.Comments["comment"] = &model.PostCommentResponse{Id: "comment", PostId: "post", UserId: "bob", Comment: "original"}
+	posts.Hidden[[2]string{"post", "dave"}] = true
+
+	return posts, newTestUseCase(testRepositories{Post: posts, Block: newFakeBlockRepository()})
+}
+
+func TestPostUpdateComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  string
+		deleted bool
+		wantErr error
+	}{
+		{"author", "bob", false, nil},
+		{"post owner", "alice", false, model.ErrForbidden},
+		{"other user", "carol", false, model.ErrForbidden},
+		{"user who cannot see the post", "dave", false, model.ErrResNotFound.Error},
+		{"removed comment", "bob", true, model.ErrCommentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, useCase := newBobComment()
+			posts.Comments["comment"].IsDeleted = tt.deleted
+
+			_, err := useCase.PostUpdateComment(context.Background(), &model.UpdatePostCommentRequest{Id: "comment", UserId: tt.caller, Comment: "edited"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if edited := posts.Comments["comment"].Comment == "edited"; edited != (tt.wantErr == nil) {
+				t.Errorf("edited = %v, want %v", edited, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestPostDeleteComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  string
+		deleted bool
+		wantErr error
+	}{
+		{"author", "bob", false, nil},
+		{"post owner moderates", "alice", false, nil},
+		{"other user", "carol", false, model.ErrForbidden},
+		{"user who cannot see the post", "dave", false, model.ErrResNotFound.Error},
+		{"removed comment", "bob", true, model.ErrCommentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, useCase := newBobComment()
+			posts.Comments["comment"].IsDeleted = tt.deleted
+
+			err := useCase.PostDeleteComment(context.Background(), "comment", tt.caller)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if removed := posts.Comments["comment"].IsDeleted; removed != (tt.wantErr == nil || tt.deleted) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantErr == nil || tt.deleted)
+			}
+		})
+	}
+}
+
+func TestPostCommentListRequestValidate(t *testing.T) {
+	tests := map[int]bool{0: true, 10: true, 100: true, -1: false, 101: false}
+
+	for limit, valid := range tests {
+		if err := (model.PostCommentListRequest{Limit: limit}).Validate(); (err == nil) != valid {
+			t.Errorf("limit %d: Validate() = %v, want valid %v", limit, err, valid)
+		}
+	}
+}
+
+func TestPostCommentList(t *testing.T) {
+	posts, useCase := newBobComment()
+	posts.CommentPage = []model.PostCommentUserResponse{
+		treeComment("a", "", 0), treeComment("b", "a", 1), treeComment("c", "", 0),
+	}
+
+	result, err := useCase.PostCommentList(context.Background(), &model.PostCommentListRequest{PostId: "post", UserId: "carol"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := renderCommentTree(result.Data); got != "a(b) c" {
+		t.Errorf("tree = %q, want %q", got, "a(b) c")
+	}
+
+	if limit := posts.CommentListRequests[0].Limit; limit != model.CommentPageLimit {
+		t.Errorf("limit = %d, want the default %d", limit, model.CommentPageLimit)
+	}
+
+	_, err = useCase.PostCommentList(context.Background(), &model.PostCommentListRequest{PostId: "post", UserId: "dave"})
+	if !errors.Is(err, model.ErrResNotFound.Error) {
+		t.Errorf("hidden post: err = %v, want %v", err, model.ErrResNotFound.Error)
+	}
+
+	if len(posts.CommentListRequests) != 1 {
+		t.Error("comments of a hidden post were listed")
+	}
+}
+
+func TestPostCommentListAcrossBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocked [2]string
+	}{
+		{"post author blocked the caller", [2]string{"alice", "carol"}},
+		{"caller blocked the post author", [2]string{"carol", "alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, _ := newBobComment()
+			posts.CommentPage = []model.PostCommentUserResponse{treeComment("a", "", 0)}
+
+			blocks := newFakeBlockRepository()
+			blocks.Blocks[tt.blocked] = model.BlockTypeBlock
+			useCase := newTestUseCase(testRepositories{Post: posts, Block: blocks})
+
+			result, err := useCase.PostCommentList(context.Background(), &model.PostCommentListRequest{PostId: "post", UserId: "carol"})
+			if !errors.Is(err, model.ErrBlocked) {
+				t.Errorf("err = %v, want %v", err, model.ErrBlocked)
+			}
+
+			if len(result.Data) != 0 || len(posts.CommentListRequests) != 0 {
+				t.Error("comments listed across a block")
+			}
+		})
+	}
+}
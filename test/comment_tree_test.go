@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+// treeComment returns a comment with the given id, parent and depth.
+func treeComment(id string, parentId string, depth int) model.PostCommentUserResponse {
+	return model.PostCommentUserResponse{PostCommentResponse: model.PostCommentResponse{Id: id, PostId: "post", ParentCommentId: parentId, Depth: depth}}
+}
+
+// renderCommentTree writes the tree as "a(b c(d)) e", placeholders are prefixed with "?".
+func renderCommentTree(comments []model.PostCommentUserResponse) string {
+	rendered := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		node := comment.Id
+		if comment.IsPlaceholder {
+			node = "?" + node
+		}
+		if len(comment.Replies) > 0 {
+			node += "(" + renderCommentTree(comment.Replies) + ")"
+		}
+		rendered = append(rendered, node)
+	}
+
+	return strings.Join(rendered, " ")
+}
+
+func TestPostListBuildsCommentTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []model.PostCommentUserResponse
+		want     string
+	}{
+		{
+			name:     "flat comments keep their order",
+			comments: []model.PostCommentUserResponse{treeComment("a", "", 0), treeComment("b", "", 0), treeComment("c", "", 0)},
+			want:     "a b c",
+		},
+		{
+			name: "replies nest to the full depth",
+			comments: []model.PostCommentUserResponse{
+				treeComment("a", "", 0), treeComment("b", "a", 1), treeComment("c", "b", 2), treeComment("d", "c", 3),
+			},
+			want: "a(b(c(d)))",
+		},
+		{
+			name: "replies stay in creation order under interleaved roots",
+			comments: []model.PostCommentUserResponse{
+				treeComment("a", "", 0), treeComment("b", "", 0), treeComment("c", "a", 1), treeComment("d", "b", 1), treeComment("e", "a", 1),
+			},
+			want: "a(c e) b(d)",
+		},
+		{
+			name: "orphans share one placeholder where the first of them was",
+			comments: []model.PostCommentUserResponse{
+				treeComment("a", "", 0), treeComment("b", "x", 1), treeComment("c", "", 0), treeComment("d", "x", 1),
+			},
+			want: "a ?x(b d) c",
+		},
+		{
+			name: "replies to an orphan nest under it",
+			comments: []model.PostCommentUserResponse{
+				treeComment("b", "x", 2), treeComment("c", "b", 3), treeComment("d", "y", 1),
+			},
+			want: "?x(b(c)) ?y(d)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uc := newTestUseCase(testRepositories{Post: &fakePostRepository{Posts: []model.PostListResponse{{PostId: "post", Comments: test.comments}}}})
+
+			result, err := uc.PostList(context.Background(), &model.PostListRequest{Limit: 5})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := renderCommentTree(result.Data[0].Comments); got != test.want {
+				t.Errorf("tree = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCommentPlaceholderKeepsParentPosition(t *testing.T) {
+	comments := []model.PostCommentUserResponse{treeComment("b", "x", 2)}
+	uc := newTestUseCase(testRepositories{Post: &fakePostRepository{Posts: []model.PostListResponse{{PostId: "post", Comments: comments}}}})
+
+	result, err := uc.PostList(context.Background(), &model.PostListRequest{Limit: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	placeholder := result.Data[0].Comments[0]
+	if !placeholder.IsPlaceholder || placeholder.Id != "x" || placeholder.PostId != "post" || placeholder.Depth != 1 {
+		t.Errorf("unexpected placeholder %+v", placeholder.PostCommentResponse)
+	}
+
+	if placeholder.Replies[0].IsPlaceholder {
+		t.Error("loaded reply marked as placeholder")
+	}
+}
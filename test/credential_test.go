@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := map[string]string{
+		"User@Example.COM":       "user@example.com",
+		"  user@example.com \t":  "user@example.com",
+		"already@normalized.com": "already@normalized.com",
+	}
+
+	for input, want := range tests {
+		if got := helper.NormalizeEmail(input); got != want {
+			t.Errorf("NormalizeEmail(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := map[string]string{
+		"+6281234567890":      "+6281234567890",
+		" +62 812-3456-7890 ": "+6281234567890",
+		"0062 (812) 34567890": "+6281234567890",
+		"+62.812.3456.7890":   "+6281234567890",
+		"+62 812 abc":         "+62812abc",
+	}
+
+	for input, want := range tests {
+		if got := helper.NormalizePhone(input); got != want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDetectCredential(t *testing.T) {
+	tests := []struct {
+		identifier string
+		kind       model.UserCredentialType
+		value      string
+		ok         bool
+	}{
+		{"User@Example.com", model.Email, "user@example.com", true},
+		{"+62 812-3456-7890", model.Phone, "+6281234567890", true},
+		{"0062 81234567890", model.Phone, "+6281234567890", true},
+		{"081234567890", "", "081234567890", false},
+		{"not an identifier", "", "not an identifier", false},
+	}
+
+	for _, test := range tests {
+		kind, value, ok := helper.DetectCredential(test.identifier)
+		if kind != test.kind || value != test.value || ok != test.ok {
+			t.Errorf("DetectCredential(%q) = %q, %q, %v, want %q, %q, %v", test.identifier, kind, value, ok, test.kind, test.value, test.ok)
+		}
+	}
+}
+
+func TestReplaceCredentialValidation(t *testing.T) {
+	valid := model.UserReplaceCredentialRequest{
+		CredentialType:  model.Email,
+		CredentialValue: "new@example.com",
+		Password:        "password",
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+
+	invalid := []model.UserReplaceCredentialRequest{
+		{CredentialType: model.Email, CredentialValue: "not-an-email", Password: "password"},
+		{CredentialType: model.Phone, CredentialValue: "new@example.com", Password: "password"},
+		{CredentialType: model.Email, CredentialValue: "new@example.com"},
+	}
+	for _, request := range invalid {
+		if err := request.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", request)
+		}
+	}
+
+	remove := model.UserRemoveCredentialRequest{CredentialType: "username", Password: "password"}
+	if err := remove.Validate(); err == nil {
+		t.Error("Validate() of unknown credential type = nil, want error")
+	}
+}
+
+func TestRemoveCredentialThrottlesWrongPasswords(t *testing.T) {
+	user := passwordUser(t, "secret1")
+	uc := newTestUseCase(testRepositories{User: newFakeUserRepository(newFakeSessions(), user)})
+
+	request := &model.UserRemoveCredentialRequest{Id: user.Id, CredentialType: model.Email, Password: "wrong"}
+	for i := 0; i <= helper.CredentialLoginThrottle.FreeAttempts; i++ {
+		err := uc.UserRemoveCredential(context.Background(), request)
+		if !errors.Is(err, model.ErrPasswordNotMatch) {
+			t.Fatalf("attempt %d: err = %v, want %v", i+1, err, model.ErrPasswordNotMatch)
+		}
+	}
+
+	request.Password = "secret1"
+	err := uc.UserRemoveCredential(context.Background(), request)
+
+	var locked *model.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want *model.LoginLockedError", err)
+	}
+}
+
+func TestReplaceCredentialChecksExistingCredential(t *testing.T) {
+	user := passwordUser(t, "secret1")
+	other := passwordUser(t, "secret2")
+	other.Email.String = "taken@example.com"
+
+	users := newFakeUserRepository(newFakeSessions(), user, other)
+	uc := newTestUseCase(testRepositories{User: users})
+
+	request := &model.UserReplaceCredentialRequest{Id: user.Id, CredentialType: model.Email, CredentialValue: "taken@example.com", Password: "secret1"}
+	_, err := uc.UserReplaceCredential(context.Background(), request)
+	if !errors.Is(err, model.ErrUserAlreadyExists) {
+		t.Errorf("taken credential: err = %v, want %v", err, model.ErrUserAlreadyExists)
+	}
+
+	users.FindErr = model.ErrInternalDatabase
+	request.CredentialValue = "free@example.com"
+	_, err = uc.UserReplaceCredential(context.Background(), request)
+	if !errors.Is(err, model.ErrInternalDatabase) {
+		t.Errorf("failed lookup: err = %v, want %v", err, model.ErrInternalDatabase)
+	}
+}
@@ -0,0 +1,44 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 20, 10, 15, 30, 123456000, time.FixedZone("WIB", 7*60*60))
+	id := "3f2b8f9e-6a1c-4c36-9a57-2a5f0c1d9e11"
+
+	cursor := helper.EncodeCursor(createdAt, id)
+
+	decodedAt, decodedId, err := helper.DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !decodedAt.Equal(createdAt) {
+		t.Errorf("created at = %v, want %v", decodedAt, createdAt)
+	}
+
+	if decodedId != id {
+		t.Errorf("id = %q, want %q", decodedId, id)
+	}
+}
+
+func TestCursorRejectsGarbage(t *testing.T) {
+	cursors := []string{
+		"not base64 !!",
+		helper.EncodeCursor(time.Now(), "not-a-uuid"),
+		"MjAyNC0wMy0yMA", // "2024-03-20" without an id
+	}
+
+	for _, cursor := range cursors {
+		if _, _, err := helper.DecodeCursor(cursor); !errors.Is(err, model.ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) error = %v, want %v", cursor, err, model.ErrInvalidCursor)
+		}
+	}
+}
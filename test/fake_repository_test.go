@@ -0,0 +1,628 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/notifier"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+	"github.com/Dzikuri/openidea-segokuning/internal/usecase"
+	"github.com/rs/zerolog"
+)
+
+// testRepositories holds the repositories a usecase test needs, the others stay nil.
+type testRepositories struct {
+	User          repository.RepositoryUser
+	Friend        repository.RepositoryFriend
+	Post          repository.RepositoryPost
+	Block         repository.RepositoryBlock
+	Reaction      repository.RepositoryReaction
+	Session       repository.RepositorySession
+	LoginAttempt  repository.RepositoryLoginAttempt
+	Verification  repository.RepositoryVerification
+	PasswordReset repository.RepositoryPasswordReset
+	Account       repository.RepositoryAccount
+	Notifier      notifier.Notifier
+}
+
+func newTestUseCase(r testRepositories) usecase.UseCase {
+	if r.LoginAttempt == nil {
+		r.LoginAttempt = repository.NewLoginAttemptMemoryRepository()
+	}
+	if r.Notifier == nil {
+		r.Notifier = &fakeNotifier{}
+	}
+
+	return usecase.NewUseCase(zerolog.Nop(), r.User, r.Friend, r.Post, r.Block, r.Reaction, r.Session, r.LoginAttempt, r.Verification, r.PasswordReset, r.Account, r.Notifier)
+}
+
+type fakeNotifier struct {
+	mu   sync.Mutex
+	Sent []model.Notification
+	// Err fails every Send when set.
+	Err error
+}
+
+func (n *fakeNotifier) Send(ctx context.Context, notification model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.Err != nil {
+		return n.Err
+	}
+
+	n.Sent = append(n.Sent, notification)
+	return nil
+}
+
+// fakeFriendRepository keeps friendships and friend requests in memory, methods a test does not need panic through the nil interface.
+type fakeFriendRepository struct {
+	repository.RepositoryFriend
+
+	mu       sync.Mutex
+	Users    map[string]bool
+	Friends  map[[2]string]bool
+	Requests map[string]*model.FriendRequestResponse
+	// ListRequests records what FindAllFriendRequest was asked for.
+	ListRequests []model.GetFriendRequestListRequest
+}
+
+func newFakeFriendRepository(users ...string) *fakeFriendRepository {
+	r := &fakeFriendRepository{
+		Users:    map[string]bool{},
+		Friends:  map[[2]string]bool{},
+		Requests: map[string]*model.FriendRequestResponse{},
+	}
+	for _, user := range users {
+		r.Users[user] = true
+	}
+	return r
+}
+
+func (r *fakeFriendRepository) CheckAlreadyFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.Users[friendID] {
+		return nil, 404, model.ErrResNotFound.Error
+	}
+
+	if r.Friends[[2]string{userID, friendID}] {
+		return nil, 1, nil
+	}
+
+	return nil, 0, nil
+}
+
+func (r *fakeFriendRepository) CreateFriendRequest(ctx context.Context, request model.FriendRequest) (*model.FriendRequestResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	friendRequest := &model.FriendRequestResponse{
+		Id:         "request-" + request.UserId + "-" + request.FriendId,
+		SenderId:   request.UserId,
+		ReceiverId: request.FriendId,
+		Status:     model.FriendRequestPending,
+	}
+	r.Requests[friendRequest.Id] = friendRequest
+
+	copied := *friendRequest
+	return &copied, nil
+}
+
+func (r *fakeFriendRepository) FindFriendRequestById(ctx context.Context, id string) (*model.FriendRequestResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	friendRequest, ok := r.Requests[id]
+	if !ok {
+		return nil, model.ErrFriendRequestNotFound
+	}
+
+	copied := *friendRequest
+	return &copied, nil
+}
+
+func (r *fakeFriendRepository) FindPendingFriendRequest(ctx context.Context, senderID string, receiverID string) (*model.FriendRequestResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, friendRequest := range r.Requests {
+		if friendRequest.SenderId == senderID && friendRequest.ReceiverId == receiverID && friendRequest.Status == model.FriendRequestPending {
+			copied := *friendRequest
+			return &copied, nil
+		}
+	}
+
+	return nil, model.ErrFriendRequestNotFound
+}
+
+// FindAllFriendRequest lists the pending requests received by, or for outgoing sent by, request.UserId.
+func (r *fakeFriendRepository) FindAllFriendRequest(ctx context.Context, request model.GetFriendRequestListRequest) ([]model.FriendRequestResponse, model.MetaDataResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ListRequests = append(r.ListRequests, request)
+
+	friendRequests := make([]model.FriendRequestResponse, 0)
+	for _, friendRequest := range r.Requests {
+		owner := friendRequest.ReceiverId
+		if request.Type == model.FriendRequestOutgoing {
+			owner = friendRequest.SenderId
+		}
+
+		if owner == request.UserId && friendRequest.Status == model.FriendRequestPending {
+			friendRequests = append(friendRequests, *friendRequest)
+		}
+	}
+
+	return friendRequests, model.MetaDataResponse{Total: len(friendRequests), Limit: request.Limit, Offset: request.Offset}, nil
+}
+
+func (r *fakeFriendRepository) UpdateFriendRequestStatus(ctx context.Context, id string, status model.FriendRequestStatus) (*model.FriendRequestResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	friendRequest, ok := r.Requests[id]
+	if !ok {
+		return nil, model.ErrFriendRequestNotFound
+	}
+
+	if friendRequest.Status != model.FriendRequestPending {
+		return nil, model.ErrFriendRequestNotPending
+	}
+
+	friendRequest.Status = status
+	copied := *friendRequest
+	return &copied, nil
+}
+
+func (r *fakeFriendRepository) AcceptFriendRequest(ctx context.Context, id string) (*model.FriendRequestResponse, error) {
+	friendRequest, err := r.UpdateFriendRequestStatus(ctx, id, model.FriendRequestAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Friends[[2]string{friendRequest.SenderId, friendRequest.ReceiverId}] = true
+	r.Friends[[2]string{friendRequest.ReceiverId, friendRequest.SenderId}] = true
+
+	return friendRequest, nil
+}
+
+func (r *fakeFriendRepository) RemoveFriend(ctx context.Context, userID string, friendID string) (*model.FriendResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.Friends, [2]string{userID, friendID})
+	delete(r.Friends, [2]string{friendID, userID})
+
+	return nil, nil
+}
+
+// fakeBlockRepository keeps blocks and mutes in memory, IsBlocked ignores mutes like the query it stands for.
+type fakeBlockRepository struct {
+	mu     sync.Mutex
+	Blocks map[[2]string]model.BlockType
+}
+
+func newFakeBlockRepository() *fakeBlockRepository {
+	return &fakeBlockRepository{Blocks: map[[2]string]model.BlockType{}}
+}
+
+func (r *fakeBlockRepository) Block(ctx context.Context, request model.BlockRequest) (*model.BlockResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Blocks[[2]string{request.UserId, request.BlockedUserId}] = request.Type
+
+	return &model.BlockResponse{UserId: request.UserId, BlockedUserId: request.BlockedUserId, Type: request.Type}, nil
+}
+
+func (r *fakeBlockRepository) Unblock(ctx context.Context, userID string, blockedUserID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.Blocks[[2]string{userID, blockedUserID}]; !ok {
+		return model.ErrNotBlocked
+	}
+
+	delete(r.Blocks, [2]string{userID, blockedUserID})
+	return nil
+}
+
+func (r *fakeBlockRepository) IsBlocked(ctx context.Context, userID string, otherUserID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.Blocks[[2]string{userID, otherUserID}] == model.BlockTypeBlock || r.Blocks[[2]string{otherUserID, userID}] == model.BlockTypeBlock, nil
+}
+
+// fakeSessions records which sessions of which user are still active.
+type fakeSessions struct {
+	mu     sync.Mutex
+	Active map[string]map[string]bool
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{Active: map[string]map[string]bool{}}
+}
+
+func (s *fakeSessions) Start(userId string, sessionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Active[userId] == nil {
+		s.Active[userId] = map[string]bool{}
+	}
+	s.Active[userId][sessionId] = true
+}
+
+func (s *fakeSessions) RevokeAll(userId string, exceptSessionId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sessionId := range s.Active[userId] {
+		if sessionId != exceptSessionId {
+			s.Active[userId][sessionId] = false
+		}
+	}
+}
+
+func (s *fakeSessions) IsActive(userId string, sessionId string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Active[userId][sessionId]
+}
+
+// fakeSessionRepository revokes and checks sessions held in fakeSessions, methods a test does not need panic through the nil interface.
+type fakeSessionRepository struct {
+	repository.RepositorySession
+
+	Sessions *fakeSessions
+	Touched  []string
+}
+
+func (r *fakeSessionRepository) RevokeSession(ctx context.Context, sessionId string, userId string) error {
+	if !r.Sessions.IsActive(userId, sessionId) {
+		return model.ErrSessionNotFound
+	}
+
+	r.Sessions.mu.Lock()
+	defer r.Sessions.mu.Unlock()
+
+	r.Sessions.Active[userId][sessionId] = false
+	return nil
+}
+
+func (r *fakeSessionRepository) IsSessionActive(ctx context.Context, sessionId string, userId string) (bool, error) {
+	return r.Sessions.IsActive(userId, sessionId), nil
+}
+
+func (r *fakeSessionRepository) TouchSession(ctx context.Context, sessionId string) error {
+	r.Touched = append(r.Touched, sessionId)
+	return nil
+}
+
+// fakePostRepository serves a fixed feed and keeps single posts in memory, methods a test does not need panic through the nil interface.
+type fakePostRepository struct {
+	repository.RepositoryPost
+
+	mu           sync.Mutex
+	Posts        []model.PostListResponse
+	ListRequests []model.PostListRequest
+	Store        map[string]*model.PostResponse
+	Comments     map[string]*model.PostCommentResponse
+	// CommentPage is what PostCommentList returns, the requests it was called with are kept in CommentListRequests.
+	CommentPage         []model.PostCommentUserResponse
+	CommentListRequests []model.PostCommentListRequest
+	// Hidden holds the (post id, viewer id) pairs FindPostById treats as not visible.
+	Hidden map[[2]string]bool
+}
+
+func newFakePostRepository(posts ...*model.PostResponse) *fakePostRepository {
+	r := &fakePostRepository{Store: map[string]*model.PostResponse{}, Comments: map[string]*model.PostCommentResponse{}, Hidden: map[[2]string]bool{}}
+	for _, post := range posts {
+		r.Store[post.Id] = post
+	}
+	return r
+}
+
+// PostList returns Posts, only the one with request.PostId when it is set.
+func (r *fakePostRepository) PostList(ctx context.Context, request *model.PostListRequest) ([]model.PostListResponse, model.MetaDataResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ListRequests = append(r.ListRequests, *request)
+
+	posts := make([]model.PostListResponse, 0, len(r.Posts))
+	for _, post := range r.Posts {
+		if request.PostId == "" || post.PostId == request.PostId {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, model.MetaDataResponse{Total: len(posts), Limit: request.Limit}, nil
+}
+
+func (r *fakePostRepository) FindPostById(ctx context.Context, id string, viewerId string) (*model.PostResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.Store[id]
+	if !ok || r.Hidden[[2]string{id, viewerId}] {
+		return nil, model.ErrResNotFound.Error
+	}
+
+	copied := *post
+	return &copied, nil
+}
+
+func (r *fakePostRepository) CreatePost(ctx context.Context, request *model.CreatePostRequest) (*model.PostResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post := &model.PostResponse{Id: fmt.Sprintf("post-%d", len(r.Store)+1), UserId: request.UserId, Content: request.Content, Tags: request.Tags, Visibility: request.Visibility}
+	r.Store[post.Id] = post
+
+	copied := *post
+	return &copied, nil
+}
+
+func (r *fakePostRepository) CreatePostComment(ctx context.Context, request *model.CreatePostCommentRequest) (*model.PostCommentResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment := &model.PostCommentResponse{Id: fmt.Sprintf("comment-%d", len(r.Comments)+1), PostId: request.PostId, UserId: request.UserId, ParentCommentId: request.ParentCommentId, Depth: request.Depth, Comment: request.Comment}
+	r.Comments[comment.Id] = comment
+
+	copied := *comment
+	return &copied, nil
+}
+
+func (r *fakePostRepository) UpdatePost(ctx context.Context, request *model.UpdatePostRequest) (*model.PostResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.Store[request.Id]
+	if !ok || post.UserId != request.UserId {
+		return nil, model.ErrResNotFound.Error
+	}
+
+	if request.Content != "" {
+		post.Content = request.Content
+	}
+	if request.Tags != nil {
+		post.Tags = request.Tags
+	}
+
+	copied := *post
+	return &copied, nil
+}
+
+func (r *fakePostRepository) DeletePost(ctx context.Context, id string, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.Store[id]
+	if !ok || post.UserId != userId {
+		return model.ErrResNotFound.Error
+	}
+
+	delete(r.Store, id)
+	return nil
+}
+
+func (r *fakePostRepository) PostHistory(ctx context.Context, id string) ([]model.PostHistoryResponse, error) {
+	return []model.PostHistoryResponse{}, nil
+}
+
+func (r *fakePostRepository) FindPostCommentById(ctx context.Context, id string) (*model.PostCommentResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment, ok := r.Comments[id]
+	if !ok {
+		return nil, model.ErrCommentNotFound
+	}
+
+	copied := *comment
+	return &copied, nil
+}
+
+func (r *fakePostRepository) PostCommentList(ctx context.Context, request *model.PostCommentListRequest) ([]model.PostCommentUserResponse, model.MetaDataResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.CommentListRequests = append(r.CommentListRequests, *request)
+
+	comments := make([]model.PostCommentUserResponse, len(r.CommentPage))
+	copy(comments, r.CommentPage)
+
+	return comments, model.MetaDataResponse{Total: len(comments), Limit: request.Limit}, nil
+}
+
+func (r *fakePostRepository) UpdatePostComment(ctx context.Context, request *model.UpdatePostCommentRequest) (*model.PostCommentResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment, ok := r.Comments[request.Id]
+	if !ok || comment.UserId != request.UserId || comment.IsDeleted {
+		return nil, model.ErrCommentNotFound
+	}
+
+	comment.Comment = request.Comment
+	copied := *comment
+	return &copied, nil
+}
+
+func (r *fakePostRepository) DeletePostComment(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comment, ok := r.Comments[id]
+	if !ok || comment.IsDeleted {
+		return model.ErrCommentNotFound
+	}
+
+	comment.IsDeleted = true
+	return nil
+}
+
+// fakeReactionRepository records the reactions it was asked to store, methods a test does not need panic through the nil interface.
+type fakeReactionRepository struct {
+	repository.RepositoryReaction
+
+	Reacted []model.ReactionRequest
+}
+
+func (r *fakeReactionRepository) React(ctx context.Context, request model.ReactionRequest) (*model.ReactionResponse, error) {
+	r.Reacted = append(r.Reacted, request)
+
+	return &model.ReactionResponse{UserId: request.UserId, PostId: request.PostId, CommentId: request.CommentId, Type: request.Type}, nil
+}
+
+// fakeUserRepository keeps users in memory, methods a test does not need panic through the nil interface.
+type fakeUserRepository struct {
+	repository.RepositoryUser
+
+	mu       sync.Mutex
+	Users    map[string]*model.User
+	Sessions *fakeSessions
+	// FindErr is returned by FindByEmail and FindByPhone when set.
+	FindErr error
+}
+
+func newFakeUserRepository(sessions *fakeSessions, users ...*model.User) *fakeUserRepository {
+	r := &fakeUserRepository{Users: map[string]*model.User{}, Sessions: sessions}
+	for _, user := range users {
+		r.Users[user.Id.String()] = user
+	}
+	return r
+}
+
+func (r *fakeUserRepository) FindById(ctx context.Context, id string) (*model.User, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.Users[id]
+	if !ok {
+		return nil, 404, model.ErrUserNotFound
+	}
+
+	copied := *user
+	return &copied, 200, nil
+}
+
+func (r *fakeUserRepository) find(match func(user *model.User) bool) (bool, *model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.FindErr != nil {
+		return false, nil, r.FindErr
+	}
+
+	for _, user := range r.Users {
+		if match(user) {
+			copied := *user
+			return true, &copied, nil
+		}
+	}
+
+	return false, nil, model.ErrUserNotFound
+}
+
+func (r *fakeUserRepository) FindByEmail(ctx context.Context, request *model.UserAuthRequest) (bool, *model.User, error) {
+	return r.find(func(user *model.User) bool { return user.Email.Valid && user.Email.String == request.CredentialValue })
+}
+
+func (r *fakeUserRepository) FindByPhone(ctx context.Context, request *model.UserAuthRequest) (bool, *model.User, error) {
+	return r.find(func(user *model.User) bool { return user.Phone.Valid && user.Phone.String == request.CredentialValue })
+}
+
+func (r *fakeUserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string, exceptSessionId string) error {
+	r.mu.Lock()
+	user, ok := r.Users[id]
+	if ok {
+		user.Password = passwordHash
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return model.ErrUserNotFound
+	}
+
+	r.Sessions.RevokeAll(id, exceptSessionId)
+	return nil
+}
+
+// fakePasswordResetRepository keeps reset tokens in memory with the same single-use and expiry rules as the queries.
+type fakePasswordResetRepository struct {
+	mu     sync.Mutex
+	Now    func() time.Time
+	Resets map[string]*fakePasswordReset
+	Users  *fakeUserRepository
+}
+
+type fakePasswordReset struct {
+	model.PasswordReset
+	CreatedAt time.Time
+	Used      bool
+}
+
+func newFakePasswordResetRepository(users *fakeUserRepository) *fakePasswordResetRepository {
+	return &fakePasswordResetRepository{Now: time.Now, Resets: map[string]*fakePasswordReset{}, Users: users}
+}
+
+func (r *fakePasswordResetRepository) CreatePasswordReset(ctx context.Context, userId string, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.Now()
+	for _, reset := range r.Resets {
+		if reset.UserId == userId && now.Sub(reset.CreatedAt) < model.PasswordResetResendInterval {
+			return model.ErrPasswordResetTooSoon
+		}
+	}
+
+	for _, reset := range r.Resets {
+		if reset.UserId == userId {
+			reset.Used = true
+		}
+	}
+
+	r.Resets[tokenHash] = &fakePasswordReset{PasswordReset: model.PasswordReset{Id: tokenHash, UserId: userId, ExpiresAt: expiresAt}, CreatedAt: now}
+	return nil
+}
+
+func (r *fakePasswordResetRepository) FindPasswordReset(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reset, ok := r.Resets[tokenHash]
+	if !ok || reset.Used || !reset.ExpiresAt.After(r.Now()) {
+		return nil, model.ErrInvalidPasswordReset
+	}
+
+	copied := reset.PasswordReset
+	return &copied, nil
+}
+
+func (r *fakePasswordResetRepository) ResetPassword(ctx context.Context, reset *model.PasswordReset, passwordHash string) error {
+	r.mu.Lock()
+	stored, ok := r.Resets[reset.Id]
+	if !ok || stored.Used {
+		r.mu.Unlock()
+		return model.ErrInvalidPasswordReset
+	}
+	stored.Used = true
+	r.mu.Unlock()
+
+	return r.Users.UpdatePassword(ctx, reset.UserId, passwordHash, "")
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+func TestSendFriendRequest(t *testing.T) {
+	friends := newFakeFriendRepository("alice", "bob")
+	useCase := newTestUseCase(testRepositories{Friend: friends, Block: newFakeBlockRepository()})
+
+	result, err := useCase.SendFriendRequest(context.Background(), "alice", "bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != model.FriendRequestPending {
+		t.Errorf("status = %q, want %q", result.Status, model.FriendRequestPending)
+	}
+
+	if friends.Friends[[2]string{"alice", "bob"}] {
+		t.Error("sending a request made them friends")
+	}
+
+	_, err = useCase.SendFriendRequest(context.Background(), "alice", "ghost")
+	if !errors.Is(err, model.ErrResNotFound.Error) {
+		t.Errorf("unknown user: err = %v, want %v", err, model.ErrResNotFound.Error)
+	}
+}
+
+func TestSendFriendRequestBackAccepts(t *testing.T) {
+	friends := newFakeFriendRepository("alice", "bob")
+	useCase := newTestUseCase(testRepositories{Friend: friends, Block: newFakeBlockRepository()})
+
+	if _, err := useCase.SendFriendRequest(context.Background(), "alice", "bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := useCase.SendFriendRequest(context.Background(), "bob", "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Status != model.FriendRequestAccepted || result.SenderId != "alice" {
+		t.Errorf("result = %+v, want alice's request accepted", result)
+	}
+
+	if len(friends.Requests) != 1 {
+		t.Errorf("requests = %d, want the incoming one reused", len(friends.Requests))
+	}
+
+	_, err = useCase.SendFriendRequest(context.Background(), "alice", "bob")
+	if !errors.Is(err, model.ErrAlreadyBeFriend) {
+		t.Errorf("already friends: err = %v, want %v", err, model.ErrAlreadyBeFriend)
+	}
+}
+
+func TestFriendRequestActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		actor      string
+		wantErr    error
+		wantStatus model.FriendRequestStatus
+	}{
+		{"receiver accepts", "accept", "bob", nil, model.FriendRequestAccepted},
+		{"sender cannot accept", "accept", "alice", model.ErrFriendRequestNotFound, model.FriendRequestPending},
+		{"receiver rejects", "reject", "bob", nil, model.FriendRequestRejected},
+		{"sender cannot reject", "reject", "alice", model.ErrFriendRequestNotFound, model.FriendRequestPending},
+		{"sender cancels", "cancel", "alice", nil, model.FriendRequestCancelled},
+		{"receiver cannot cancel", "cancel", "bob", model.ErrFriendRequestNotFound, model.FriendRequestPending},
+		{"stranger cannot accept", "accept", "carol", model.ErrFriendRequestNotFound, model.FriendRequestPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			friends := newFakeFriendRepository("alice", "bob", "carol")
+			useCase := newTestUseCase(testRepositories{Friend: friends, Block: newFakeBlockRepository()})
+
+			sent, err := useCase.SendFriendRequest(context.Background(), "alice", "bob")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			request := model.FriendRequestActionRequest{Id: sent.Id, UserId: tt.actor}
+			switch tt.action {
+			case "accept":
+				_, err = useCase.AcceptFriendRequest(context.Background(), request)
+			case "reject":
+				_, err = useCase.RejectFriendRequest(context.Background(), request)
+			case "cancel":
+				_, err = useCase.CancelFriendRequest(context.Background(), request)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if status := friends.Requests[sent.Id].Status; status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+
+			wantFriends := tt.wantStatus == model.FriendRequestAccepted
+			if friended := friends.Friends[[2]string{"bob", "alice"}]; friended != wantFriends {
+				t.Errorf("friends = %v, want %v", friended, wantFriends)
+			}
+		})
+	}
+}
+
+func TestAcceptHandledFriendRequest(t *testing.T) {
+	useCase := newTestUseCase(testRepositories{Friend: newFakeFriendRepository("alice", "bob"), Block: newFakeBlockRepository()})
+
+	sent, err := useCase.SendFriendRequest(context.Background(), "alice", "bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := model.FriendRequestActionRequest{Id: sent.Id, UserId: "bob"}
+	if _, err = useCase.RejectFriendRequest(context.Background(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = useCase.AcceptFriendRequest(context.Background(), request)
+	if !errors.Is(err, model.ErrFriendRequestNotPending) {
+		t.Errorf("err = %v, want %v", err, model.ErrFriendRequestNotPending)
+	}
+}
+
+func TestRemoveFriend(t *testing.T) {
+	friends := newFakeFriendRepository("alice", "bob")
+	useCase := newTestUseCase(testRepositories{Friend: friends, Block: newFakeBlockRepository()})
+
+	_, err := useCase.RemoveFriend(context.Background(), "alice", "bob")
+	if !errors.Is(err, model.ErrNotFriend) {
+		t.Errorf("not friends: err = %v, want %v", err, model.ErrNotFriend)
+	}
+
+	friends.Friends[[2]string{"alice", "bob"}] = true
+	friends.Friends[[2]string{"bob", "alice"}] = true
+
+	if _, err = useCase.RemoveFriend(context.Background(), "alice", "bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(friends.Friends) != 0 {
+		t.Errorf("friends = %v, want both directions removed", friends.Friends)
+	}
+}
+
+func TestGetFriendRequestList(t *testing.T) {
+	friends := newFakeFriendRepository("alice", "bob")
+	useCase := newTestUseCase(testRepositories{Friend: friends, Block: newFakeBlockRepository()})
+
+	if _, err := useCase.SendFriendRequest(context.Background(), "alice", "bob"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		userId    string
+		kind      model.FriendRequestType
+		wantType  model.FriendRequestType
+		wantTotal int
+	}{
+		{"bob", "", model.FriendRequestIncoming, 1},
+		{"bob", model.FriendRequestOutgoing, model.FriendRequestOutgoing, 0},
+		{"alice", model.FriendRequestOutgoing, model.FriendRequestOutgoing, 1},
+		{"alice", model.FriendRequestIncoming, model.FriendRequestIncoming, 0},
+	}
+
+	for _, tt := range tests {
+		result, err := useCase.GetFriendRequestList(context.Background(), model.GetFriendRequestListRequest{UserId: tt.userId, Type: tt.kind})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		listed := friends.ListRequests[len(friends.ListRequests)-1]
+		if listed.Type != tt.wantType || listed.Limit != 5 {
+			t.Errorf("%s %q: listed with type %q limit %d, want %q limit 5", tt.userId, tt.kind, listed.Type, listed.Limit, tt.wantType)
+		}
+
+		if result.Meta.Total != tt.wantTotal {
+			t.Errorf("%s %q: total = %d, want %d", tt.userId, tt.kind, result.Meta.Total, tt.wantTotal)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/config"
+	"github.com/labstack/echo/v4"
+)
+
+// realIP returns the client ip echo reports for a request from remoteAddr carrying headers.
+func realIP(t *testing.T, trustedProxies string, remoteAddr string, headers map[string]string) string {
+	extractor, err := config.NewIPExtractor(trustedProxies)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	e := echo.New()
+	e.IPExtractor = extractor
+
+	request := httptest.NewRequest("POST", "/v1/user/login", nil)
+	request.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	return e.NewContext(request, httptest.NewRecorder()).RealIP()
+}
+
+func TestSpoofedForwardedHeadersDoNotChangeIp(t *testing.T) {
+	spoofed := []map[string]string{
+		{},
+		{echo.HeaderXForwardedFor: "1.2.3.4"},
+		{echo.HeaderXForwardedFor: "5.6.7.8, 9.9.9.9"},
+		{echo.HeaderXRealIP: "10.0.0.1"},
+	}
+
+	for _, headers := range spoofed {
+		if ip := realIP(t, "", "203.0.113.7:52100", headers); ip != "203.0.113.7" {
+			t.Errorf("headers %v: ip = %q, want the connection address", headers, ip)
+		}
+	}
+}
+
+func TestForwardedHeaderFromTrustedProxy(t *testing.T) {
+	headers := map[string]string{echo.HeaderXForwardedFor: "198.51.100.9"}
+
+	if ip := realIP(t, "10.0.0.0/8", "10.0.0.5:443", headers); ip != "198.51.100.9" {
+		t.Errorf("trusted proxy: ip = %q, want the forwarded address", ip)
+	}
+
+	if ip := realIP(t, "10.0.0.0/8", "203.0.113.7:52100", headers); ip != "203.0.113.7" {
+		t.Errorf("untrusted peer: ip = %q, want the connection address", ip)
+	}
+
+	if _, err := config.NewIPExtractor("not-a-cidr"); err == nil {
+		t.Error("NewIPExtractor(not-a-cidr) = nil error")
+	}
+}
@@ -0,0 +1,213 @@
+package test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+// writeKeyPair writes private and public PEM files for key into dir and returns their paths.
+func writeKeyPair(t *testing.T, dir string, name string, key crypto.Signer) (string, string) {
+	privateDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicDer, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePath := filepath.Join(dir, name+".pem")
+	publicPath := filepath.Join(dir, name+".pub.pem")
+
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return privatePath, publicPath
+}
+
+// clearJwtEnv resets every variable read by helper.LoadJwtConfig for the duration of the test.
+func clearJwtEnv(t *testing.T) {
+	for _, key := range []string{"JWT_SIGNING_METHOD", "JWT_SECRET", "JWT_PRIVATE_KEY_FILE", "JWT_KEY_ID", "JWT_PUBLIC_KEY_FILES", "JWT_EXPIRE_AT", "JWT_ISSUER", "JWT_AUDIENCE"} {
+		t.Setenv(key, "")
+	}
+}
+
+func loadJwtConfig(t *testing.T) {
+	config, err := helper.LoadJwtConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	helper.SetJwtConfig(config)
+	t.Cleanup(func() { helper.SetJwtConfig(nil) })
+}
+
+func signAndVerify(t *testing.T) (string, error) {
+	user := &model.User{Id: uuid.NewV4(), Name: "user name"}
+
+	token, err := helper.JwtGenerateToken(user, uuid.NewV4().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims := &helper.JwtCustomClaims{}
+	err = helper.VerifyJwt(token, claims)
+	if err == nil && claims.Id != user.Id.String() {
+		t.Errorf("claims id = %q, want %q", claims.Id, user.Id)
+	}
+
+	return token, err
+}
+
+func TestLoadJwtConfigRequiresSecretOrKey(t *testing.T) {
+	clearJwtEnv(t)
+
+	if _, err := helper.LoadJwtConfig(); err == nil {
+		t.Errorf("HS256 without JWT_SECRET loaded")
+	}
+
+	t.Setenv("JWT_SIGNING_METHOD", "RS256")
+	if _, err := helper.LoadJwtConfig(); err == nil {
+		t.Errorf("RS256 without JWT_PRIVATE_KEY_FILE loaded")
+	}
+
+	t.Setenv("JWT_SIGNING_METHOD", "none")
+	if _, err := helper.LoadJwtConfig(); err == nil {
+		t.Errorf("unsupported method loaded")
+	}
+}
+
+func TestLoadJwtConfigValidatesExpireAt(t *testing.T) {
+	clearJwtEnv(t)
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	for _, value := range []string{"0", "-1", "two"} {
+		t.Setenv("JWT_EXPIRE_AT", value)
+		if _, err := helper.LoadJwtConfig(); err == nil {
+			t.Errorf("JWT_EXPIRE_AT=%q loaded", value)
+		}
+	}
+
+	t.Setenv("JWT_EXPIRE_AT", "5")
+	config, err := helper.LoadJwtConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if config.ExpireAt.Hours() != 5 {
+		t.Errorf("expire at = %v, want 5h", config.ExpireAt)
+	}
+}
+
+func TestJwtIssuerAndAudience(t *testing.T) {
+	clearJwtEnv(t)
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("JWT_ISSUER", "segokuning")
+	t.Setenv("JWT_AUDIENCE", "segokuning-api")
+	loadJwtConfig(t)
+
+	token, err := signAndVerify(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("JWT_AUDIENCE", "other-api")
+	loadJwtConfig(t)
+
+	if err := helper.VerifyJwt(token, &helper.JwtCustomClaims{}); err == nil {
+		t.Errorf("token for another audience verified")
+	}
+}
+
+func TestJwtAsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for method, key := range map[string]crypto.Signer{"RS256": rsaKey, "EdDSA": edKey} {
+		t.Run(method, func(t *testing.T) {
+			clearJwtEnv(t)
+			privatePath, _ := writeKeyPair(t, t.TempDir(), "key", key)
+			t.Setenv("JWT_SIGNING_METHOD", method)
+			t.Setenv("JWT_PRIVATE_KEY_FILE", privatePath)
+			loadJwtConfig(t)
+
+			if _, err := signAndVerify(t); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestJwtKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	_, oldKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldPrivate, oldPublic := writeKeyPair(t, dir, "old", oldKey)
+	newPrivate, _ := writeKeyPair(t, dir, "new", newKey)
+
+	clearJwtEnv(t)
+	t.Setenv("JWT_SIGNING_METHOD", "EdDSA")
+	t.Setenv("JWT_PRIVATE_KEY_FILE", oldPrivate)
+	t.Setenv("JWT_KEY_ID", "old")
+	loadJwtConfig(t)
+
+	oldToken, err := signAndVerify(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Rotate: sign with the new key, keep the old public key for verification
+	t.Setenv("JWT_PRIVATE_KEY_FILE", newPrivate)
+	t.Setenv("JWT_KEY_ID", "new")
+	t.Setenv("JWT_PUBLIC_KEY_FILES", "old="+oldPublic)
+	loadJwtConfig(t)
+
+	if err := helper.VerifyJwt(oldToken, &helper.JwtCustomClaims{}); err != nil {
+		t.Errorf("token of the previous key rejected: %v", err)
+	}
+
+	if _, err := signAndVerify(t); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Drop the old key: its tokens stop verifying
+	t.Setenv("JWT_PUBLIC_KEY_FILES", "")
+	loadJwtConfig(t)
+
+	if err := helper.VerifyJwt(oldToken, &helper.JwtCustomClaims{}); err == nil {
+		t.Errorf("token of a removed key verified")
+	}
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+)
+
+func TestLoginThrottleDelay(t *testing.T) {
+	throttle := helper.LoginThrottle{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        10 * time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: time.Hour,
+	}
+
+	tests := map[int]time.Duration{
+		0:  0,
+		3:  0,
+		4:  time.Second,
+		5:  2 * time.Second,
+		6:  4 * time.Second,
+		7:  8 * time.Second,
+		8:  10 * time.Second,
+		9:  10 * time.Second,
+		10: time.Hour,
+		50: time.Hour,
+	}
+
+	for failures, want := range tests {
+		if got := throttle.Delay(failures); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
+
+func TestLoginLockedErrorIsErrLoginLocked(t *testing.T) {
+	var err error = &model.LoginLockedError{RetryAfter: 90 * time.Second}
+
+	if !errors.Is(err, model.ErrLoginLocked) {
+		t.Errorf("errors.Is(%v, ErrLoginLocked) = false", err)
+	}
+
+	var locked *model.LoginLockedError
+	if !errors.As(err, &locked) || locked.RetryAfter != 90*time.Second {
+		t.Errorf("errors.As = %v", locked)
+	}
+}
+
+func TestLoginAttemptMemoryRepository(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewLoginAttemptMemoryRepository()
+	now := time.Now()
+
+	for i := 1; i <= 3; i++ {
+		attempt, err := store.RecordLoginFailure(ctx, "ip:127.0.0.1", now, time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if attempt.Failures != i {
+			t.Errorf("failures = %d, want %d", attempt.Failures, i)
+		}
+	}
+
+	until := now.Add(time.Minute)
+	if err := store.LockLoginAttempt(ctx, "ip:127.0.0.1", until); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	attempt, err := store.FindLoginAttempt(ctx, "ip:127.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempt.Failures != 3 || !attempt.LockedUntil.Equal(until) {
+		t.Errorf("attempt = %+v", attempt)
+	}
+
+	// A failure after the reset window starts counting again
+	attempt, err = store.RecordLoginFailure(ctx, "ip:127.0.0.1", now.Add(2*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempt.Failures != 1 {
+		t.Errorf("failures after reset window = %d, want 1", attempt.Failures)
+	}
+
+	if err := store.ResetLoginAttempt(ctx, "ip:127.0.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	attempt, err = store.FindLoginAttempt(ctx, "ip:127.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attempt.Failures != 0 || !attempt.LockedUntil.IsZero() {
+		t.Errorf("attempt after reset = %+v", attempt)
+	}
+}
+
+func TestLoginAttemptMemoryRepositorySweep(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewLoginAttemptMemoryRepository().(*repository.LoginAttemptMemoryRepository)
+	now := time.Now()
+
+	if _, err := store.RecordLoginFailure(ctx, "ip:127.0.0.1", now, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.RecordLoginFailure(ctx, "ip:127.0.0.2", now, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.LockLoginAttempt(ctx, "ip:127.0.0.2", now.Add(3*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store.Sweep(now.Add(2 * time.Hour))
+
+	stale, _ := store.FindLoginAttempt(ctx, "ip:127.0.0.1")
+	if stale.Failures != 0 {
+		t.Errorf("stale entry kept: %+v", stale)
+	}
+
+	locked, _ := store.FindLoginAttempt(ctx, "ip:127.0.0.2")
+	if locked.Failures != 1 {
+		t.Errorf("locked entry dropped: %+v", locked)
+	}
+}
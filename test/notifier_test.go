@@ -0,0 +1,44 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/notifier"
+)
+
+func TestFileNotifierAppendsJsonLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.jsonl")
+	notify := notifier.NewFileNotifier(path)
+
+	for _, to := range []string{"a@example.com", "+6281234567890"} {
+		err := notify.Send(context.Background(), model.Notification{Channel: model.Email, To: to, Subject: "Verification code", Body: "123456"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if line["to"] != "+6281234567890" || line["body"] != "123456" {
+		t.Errorf("line = %v", line)
+	}
+}
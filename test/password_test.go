@@ -0,0 +1,235 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/usecase"
+	uuid "github.com/satori/go.uuid"
+)
+
+// passwordUser returns a user whose password is password, hashed with the cheapest bcrypt cost.
+func passwordUser(t *testing.T, password string) *model.User {
+	t.Setenv("BCRYPT_SALT", "4")
+
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return &model.User{
+		Id:       uuid.NewV4(),
+		Email:    sql.NullString{String: "user@example.com", Valid: true},
+		Name:     "user name",
+		Password: hash,
+	}
+}
+
+type passwordFixture struct {
+	UseCase  usecase.UseCase
+	User     *model.User
+	Users    *fakeUserRepository
+	Resets   *fakePasswordResetRepository
+	Sessions *fakeSessions
+	Notify   *fakeNotifier
+}
+
+// newPasswordFixture returns a user with password, logged in on a phone and a laptop.
+func newPasswordFixture(t *testing.T, password string) *passwordFixture {
+	f := &passwordFixture{User: passwordUser(t, password), Sessions: newFakeSessions(), Notify: &fakeNotifier{}}
+	f.Users = newFakeUserRepository(f.Sessions, f.User)
+	f.Resets = newFakePasswordResetRepository(f.Users)
+	f.UseCase = newTestUseCase(testRepositories{User: f.Users, PasswordReset: f.Resets, Notifier: f.Notify})
+
+	f.Sessions.Start(f.User.Id.String(), "phone")
+	f.Sessions.Start(f.User.Id.String(), "laptop")
+
+	return f
+}
+
+// forgotPassword requests a reset for the fixture user and returns the token sent to them.
+func (f *passwordFixture) forgotPassword(t *testing.T) string {
+	err := f.UseCase.UserForgotPassword(context.Background(), &model.UserForgotPasswordRequest{CredentialType: model.Email, CredentialValue: f.User.Email.String})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.Notify.Sent) == 0 {
+		t.Fatal("no notification sent")
+	}
+
+	body := f.Notify.Sent[len(f.Notify.Sent)-1].Body
+	_, token, found := strings.Cut(body, "reset your password: ")
+	if !found {
+		t.Fatalf("no token in %q", body)
+	}
+	token, _, _ = strings.Cut(token, ",")
+
+	return token
+}
+
+func (f *passwordFixture) passwordIs(password string) bool {
+	user, _, _ := f.Users.FindById(context.Background(), f.User.Id.String())
+	return helper.ComparePassword(user.Password, password) == nil
+}
+
+func TestResetPasswordRevokesEverySession(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+	token := f.forgotPassword(t)
+
+	err := f.UseCase.UserResetPassword(context.Background(), &model.UserResetPasswordRequest{Token: token, NewPassword: "secret2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !f.passwordIs("secret2") {
+		t.Error("password was not replaced")
+	}
+
+	for _, session := range []string{"phone", "laptop"} {
+		if f.Sessions.IsActive(f.User.Id.String(), session) {
+			t.Errorf("session %s still active after reset", session)
+		}
+	}
+}
+
+func TestResetPasswordTokenIsSingleUse(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+	token := f.forgotPassword(t)
+
+	if err := f.UseCase.UserResetPassword(context.Background(), &model.UserResetPasswordRequest{Token: token, NewPassword: "secret2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := f.UseCase.UserResetPassword(context.Background(), &model.UserResetPasswordRequest{Token: token, NewPassword: "secret3"})
+	if !errors.Is(err, model.ErrInvalidPasswordReset) {
+		t.Errorf("second use: err = %v, want %v", err, model.ErrInvalidPasswordReset)
+	}
+
+	if !f.passwordIs("secret2") {
+		t.Error("second use replaced the password")
+	}
+}
+
+func TestResetPasswordRejectsExpiredAndReplacedTokens(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+
+	replaced := f.forgotPassword(t)
+	f.Resets.Now = func() time.Time { return time.Now().Add(model.PasswordResetResendInterval) }
+	token := f.forgotPassword(t)
+
+	err := f.UseCase.UserResetPassword(context.Background(), &model.UserResetPasswordRequest{Token: replaced, NewPassword: "secret2"})
+	if !errors.Is(err, model.ErrInvalidPasswordReset) {
+		t.Errorf("replaced token: err = %v, want %v", err, model.ErrInvalidPasswordReset)
+	}
+
+	f.Resets.Now = func() time.Time { return time.Now().Add(model.PasswordResetTTL + time.Minute) }
+
+	err = f.UseCase.UserResetPassword(context.Background(), &model.UserResetPasswordRequest{Token: token, NewPassword: "secret2"})
+	if !errors.Is(err, model.ErrInvalidPasswordReset) {
+		t.Errorf("expired token: err = %v, want %v", err, model.ErrInvalidPasswordReset)
+	}
+
+	if !f.passwordIs("secret1") {
+		t.Error("password changed with an invalid token")
+	}
+}
+
+func TestForgotPasswordIgnoresUnknownCredential(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+
+	err := f.UseCase.UserForgotPassword(context.Background(), &model.UserForgotPasswordRequest{CredentialType: model.Email, CredentialValue: "nobody@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.Notify.Sent) != 0 {
+		t.Errorf("sent %d notifications for an unknown credential", len(f.Notify.Sent))
+	}
+}
+
+func TestChangePasswordKeepsCurrentSession(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+
+	err := f.UseCase.UserChangePassword(context.Background(), &model.UserChangePasswordRequest{Id: f.User.Id, SessionId: "phone", CurrentPassword: "wrong", NewPassword: "secret2"})
+	if !errors.Is(err, model.ErrPasswordNotMatch) {
+		t.Errorf("wrong current password: err = %v, want %v", err, model.ErrPasswordNotMatch)
+	}
+
+	err = f.UseCase.UserChangePassword(context.Background(), &model.UserChangePasswordRequest{Id: f.User.Id, SessionId: "phone", CurrentPassword: "secret1", NewPassword: "secret2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !f.passwordIs("secret2") {
+		t.Error("password was not replaced")
+	}
+
+	if !f.Sessions.IsActive(f.User.Id.String(), "phone") {
+		t.Error("current session revoked")
+	}
+
+	if f.Sessions.IsActive(f.User.Id.String(), "laptop") {
+		t.Error("other session still active")
+	}
+}
+
+func TestChangePasswordThrottlesWrongPasswords(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+
+	request := &model.UserChangePasswordRequest{Id: f.User.Id, SessionId: "phone", CurrentPassword: "wrong", NewPassword: "secret2"}
+	for i := 0; i <= helper.CredentialLoginThrottle.FreeAttempts; i++ {
+		err := f.UseCase.UserChangePassword(context.Background(), request)
+		if !errors.Is(err, model.ErrPasswordNotMatch) {
+			t.Fatalf("attempt %d: err = %v, want %v", i+1, err, model.ErrPasswordNotMatch)
+		}
+	}
+
+	request.CurrentPassword = "secret1"
+	err := f.UseCase.UserChangePassword(context.Background(), request)
+
+	var locked *model.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("err = %v, want *model.LoginLockedError", err)
+	}
+
+	if !f.passwordIs("secret1") {
+		t.Error("password changed while locked")
+	}
+}
+
+func TestForgotPasswordResendInterval(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+	token := f.forgotPassword(t)
+
+	err := f.UseCase.UserForgotPassword(context.Background(), &model.UserForgotPasswordRequest{CredentialType: model.Email, CredentialValue: f.User.Email.String})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(f.Notify.Sent) != 1 {
+		t.Errorf("sent %d notifications, want the second request dropped", len(f.Notify.Sent))
+	}
+
+	err = f.UseCase.UserResetPassword(context.Background(), &model.UserResetPasswordRequest{Token: token, NewPassword: "secret2"})
+	if err != nil {
+		t.Errorf("first token stopped working: %v", err)
+	}
+}
+
+func TestForgotPasswordHidesSendFailure(t *testing.T) {
+	f := newPasswordFixture(t, "secret1")
+	f.Notify.Err = errors.New("smtp unavailable")
+
+	// NOTE An unknown credential answers nil, so a known one has to as well
+	err := f.UseCase.UserForgotPassword(context.Background(), &model.UserForgotPasswordRequest{CredentialType: model.Email, CredentialValue: f.User.Email.String})
+	if err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestPostDetail(t *testing.T) {
+	postId := uuid.NewV4().String()
+
+	posts := newFakePostRepository()
+	posts.Posts = []model.PostListResponse{{PostId: postId, Comments: []model.PostCommentUserResponse{treeComment("latest", "", 0)}}}
+	posts.CommentPage = []model.PostCommentUserResponse{treeComment("a", "", 0), treeComment("b", "a", 1)}
+	useCase := newTestUseCase(testRepositories{Post: posts})
+
+	result, err := useCase.PostDetail(context.Background(), postId, "carol")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// NOTE The detail is looked up like an explore feed entry, so visibility and blocks still apply
+	listed := posts.ListRequests[0]
+	if listed.PostId != postId || listed.UserId != "carol" || !listed.Explore || listed.Limit != 1 {
+		t.Errorf("listed with %+v, want the post for carol in explore mode", listed)
+	}
+
+	if limit := posts.CommentListRequests[0].Limit; limit != model.CommentPageLimit {
+		t.Errorf("comment limit = %d, want the default comment page %d", limit, model.CommentPageLimit)
+	}
+
+	if got := renderCommentTree(result.Comments); got != "a(b)" {
+		t.Errorf("comments = %q, want the first comment page %q", got, "a(b)")
+	}
+
+	if result.CommentMeta.Total != 2 {
+		t.Errorf("comment total = %d, want 2", result.CommentMeta.Total)
+	}
+}
+
+func TestPostDetailNotFound(t *testing.T) {
+	posts := newFakePostRepository()
+	useCase := newTestUseCase(testRepositories{Post: posts})
+
+	for _, id := range []string{"not-a-uuid", uuid.NewV4().String()} {
+		_, err := useCase.PostDetail(context.Background(), id, "carol")
+		if !errors.Is(err, model.ErrResNotFound.Error) {
+			t.Errorf("%s: err = %v, want %v", id, err, model.ErrResNotFound.Error)
+		}
+	}
+
+	if len(posts.ListRequests) != 1 {
+		t.Errorf("posts listed %d times, want only for the valid id", len(posts.ListRequests))
+	}
+
+	if len(posts.CommentListRequests) != 0 {
+		t.Error("comments listed for a missing post")
+	}
+}
@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+	"github.com/Dzikuri/openidea-segokuning/internal/usecase"
+)
+
+func TestUpdatePostRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request model.UpdatePostRequest
+		valid   bool
+	}{
+		{"content only", model.UpdatePostRequest{Content: "new content"}, true},
+		{"tags only", model.UpdatePostRequest{Tags: []string{"go"}}, true},
+		{"content and tags", model.UpdatePostRequest{Content: "new content", Tags: []string{"go"}}, true},
+		{"nothing to change", model.UpdatePostRequest{}, false},
+		{"empty tag", model.UpdatePostRequest{Tags: []string{""}}, false},
+		{"content too short", model.UpdatePostRequest{Content: "a"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.request.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate() = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestCreatePostRequestValidateVisibility(t *testing.T) {
+	tests := []struct {
+		visibility model.PostVisibility
+		valid      bool
+	}{
+		{"", true},
+		{model.PostVisibilityPublic, true},
+		{model.PostVisibilityFriends, true},
+		{model.PostVisibilityOnlyMe, true},
+		{"private", false},
+		{"PUBLIC", false},
+	}
+
+	for _, tt := range tests {
+		request := model.CreatePostRequest{Content: "hello", Tags: []string{"go"}, Visibility: tt.visibility}
+		if err := request.Validate(); (err == nil) != tt.valid {
+			t.Errorf("visibility %q: Validate() = %v, want valid %v", tt.visibility, err, tt.valid)
+		}
+	}
+}
+
+func TestPostCreateDefaultsToPublic(t *testing.T) {
+	posts := newFakePostRepository()
+	useCase := newTestUseCase(testRepositories{Post: posts})
+
+	post, err := useCase.PostCreate(context.Background(), &model.CreatePostRequest{UserId: "alice", Content: "hello", Tags: []string{"go"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if post.Visibility != model.PostVisibilityPublic || posts.Store[post.Id].Visibility != model.PostVisibilityPublic {
+		t.Fatalf("visibility = %q, want %q", post.Visibility, model.PostVisibilityPublic)
+	}
+
+	post, err = useCase.PostCreate(context.Background(), &model.CreatePostRequest{UserId: "alice", Content: "hello", Tags: []string{"go"}, Visibility: model.PostVisibilityOnlyMe})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if post.Visibility != model.PostVisibilityOnlyMe {
+		t.Fatalf("visibility = %q, want the requested %q", post.Visibility, model.PostVisibilityOnlyMe)
+	}
+}
+
+func TestPostCreateCommentNeedsVisiblePost(t *testing.T) {
+	posts, _ := newAlicePost()
+	useCase := newTestUseCase(testRepositories{Post: posts, Block: newFakeBlockRepository()})
+
+	_, err := useCase.PostCreateComment(context.Background(), &model.CreatePostCommentRequest{PostId: "post", UserId: "carol", Comment: "hi"})
+	if !errors.Is(err, model.ErrResNotFound.Error) {
+		t.Fatalf("err = %v, want %v", err, model.ErrResNotFound.Error)
+	}
+
+	if len(posts.Comments) != 0 {
+		t.Fatalf("a comment was stored on a post carol cannot see: %v", posts.Comments)
+	}
+
+	comment, err := useCase.PostCreateComment(context.Background(), &model.CreatePostCommentRequest{PostId: "post", UserId: "bob", Comment: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if comment.PostId != "post" || comment.UserId != "bob" {
+		t.Fatalf("unexpected comment %+v", comment)
+	}
+}
+
+// newAlicePost returns a post by alice that bob can see and carol cannot.
+func newAlicePost() (*fakePostRepository, usecase.UseCase) {
+	posts := newFakePostRepository(&model.PostResponse{Id: "post", UserId: "alice", Content: "original"})
+	posts.Hidden[[2]string{"post", "carol"}] = true
+
+	return posts, newTestUseCase(testRepositories{Post: posts})
+}
+
+func TestPostUpdateAndDeleteOwnership(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  string
+		wantErr error
+	}{
+		{"author", "alice", nil},
+		{"user who can see the post", "bob", model.ErrForbidden},
+		{"user who cannot see the post", "carol", model.ErrResNotFound.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, useCase := newAlicePost()
+
+			_, err := useCase.PostUpdate(context.Background(), &model.UpdatePostRequest{Id: "post", UserId: tt.caller, Content: "edited"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("update: err = %v, want %v", err, tt.wantErr)
+			}
+			if edited := posts.Store["post"].Content == "edited"; edited != (tt.wantErr == nil) {
+				t.Errorf("update: edited = %v, want %v", edited, tt.wantErr == nil)
+			}
+
+			err = useCase.PostDelete(context.Background(), "post", tt.caller)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("delete: err = %v, want %v", err, tt.wantErr)
+			}
+			if _, kept := posts.Store["post"]; kept != (tt.wantErr != nil) {
+				t.Errorf("delete: kept = %v, want %v", kept, tt.wantErr != nil)
+			}
+		})
+	}
+}
+
+func TestPostHistoryNeedsVisiblePost(t *testing.T) {
+	_, useCase := newAlicePost()
+
+	if _, err := useCase.PostHistory(context.Background(), "post", "bob"); err != nil {
+		t.Errorf("visible post: unexpected error: %v", err)
+	}
+
+	_, err := useCase.PostHistory(context.Background(), "post", "carol")
+	if !errors.Is(err, model.ErrResNotFound.Error) {
+		t.Errorf("hidden post: err = %v, want %v", err, model.ErrResNotFound.Error)
+	}
+}
+
+// feedQuery returns the page query PostRepository.PostList runs for request.
+func feedQuery(t *testing.T, request model.PostListRequest) recordedQuery {
+	db, recorder := newRecordingDB(t, nil)
+
+	_, err := newTestUseCase(testRepositories{Post: repository.NewPostRepository(db)}).PostList(context.Background(), &request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pages := recorder.Find("SELECT\n\tposts.")
+	if len(pages) != 1 {
+		t.Fatalf("ran %d page queries, want 1", len(pages))
+	}
+	return pages[0]
+}
+
+// friendsOfViewer is the condition limiting column to the viewer bound to $1 and their friends.
+func friendsOfViewer(column string) string {
+	return "(" + column + " = $1 OR EXISTS (SELECT 1 FROM friends WHERE friends.user_id = $1 AND friends.follow_user_id = " + column + "))"
+}
+
+func TestPostListDefaultFeedIsCallerAndFriends(t *testing.T) {
+	query := feedQuery(t, model.PostListRequest{UserId: "alice", Limit: 10})
+
+	if query.Args[0] != "alice" {
+		t.Fatalf("$1 = %v, want the caller", query.Args[0])
+	}
+
+	for _, column := range []string{"posts.user_id", "post_comments.user_id"} {
+		if !strings.Contains(query.Query, friendsOfViewer(column)) {
+			t.Errorf("default feed does not limit %s to the caller and their friends", column)
+		}
+	}
+}
+
+func TestPostListExploreIsNotLimitedToFriends(t *testing.T) {
+	query := feedQuery(t, model.PostListRequest{UserId: "alice", Limit: 10, Explore: true})
+
+	for _, column := range []string{"posts.user_id", "post_comments.user_id"} {
+		if strings.Contains(query.Query, friendsOfViewer(column)) {
+			t.Errorf("explore feed limits %s to the caller and their friends", column)
+		}
+	}
+
+	// NOTE Exploring still honours visibility, friends only posts need a friendship
+	if !strings.Contains(query.Query, "posts.visibility = 'public'") {
+		t.Errorf("explore feed does not check the post visibility")
+	}
+}
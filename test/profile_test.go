@@ -0,0 +1,79 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestFriendshipRelationStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation model.FriendshipRelation
+		want     model.FriendshipStatus
+	}{
+		{"stranger", model.FriendshipRelation{}, model.FriendshipNone},
+		{"own profile", model.FriendshipRelation{Self: true}, model.FriendshipSelf},
+		{"friends", model.FriendshipRelation{Friends: true}, model.FriendshipFriends},
+		{"request sent", model.FriendshipRelation{RequestSent: true}, model.FriendshipRequestSent},
+		{"request received", model.FriendshipRelation{RequestReceived: true}, model.FriendshipRequestReceived},
+		{"viewer blocked them", model.FriendshipRelation{Blocked: true}, model.FriendshipBlocked},
+		{"block wins over friendship", model.FriendshipRelation{Blocked: true, Friends: true}, model.FriendshipBlocked},
+		{"friendship wins over a stale request", model.FriendshipRelation{Friends: true, RequestReceived: true}, model.FriendshipFriends},
+		{"sent wins over received", model.FriendshipRelation{RequestSent: true, RequestReceived: true}, model.FriendshipRequestSent},
+		{"self wins over everything", model.FriendshipRelation{Self: true, Blocked: true, Friends: true}, model.FriendshipSelf},
+	}
+
+	for _, tt := range tests {
+		if got := tt.relation.Status(); got != tt.want {
+			t.Errorf("%s: Status() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindProfileRejectsInvalidId(t *testing.T) {
+	// NOTE The id is checked before the database is queried
+	_, err := repository.NewUserRepository(nil).FindProfile(context.Background(), "not-a-uuid", "viewer")
+	if !errors.Is(err, model.ErrUserNotFound) {
+		t.Errorf("err = %v, want %v", err, model.ErrUserNotFound)
+	}
+}
+
+func TestUserProfileFriendshipStatus(t *testing.T) {
+	profileId := uuid.NewV4().String()
+
+	tests := []struct {
+		name     string
+		viewerId string
+		want     model.FriendshipStatus
+	}{
+		{"friend with a stale request", uuid.NewV4().String(), model.FriendshipFriends},
+		{"own profile", profileId, model.FriendshipSelf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newRecordingDB(t, func(query string) ([]string, [][]driver.Value) {
+				columns := []string{"id", "name", "image_url", "total_friend", "created_at", "mutual_friend_count", "blocked", "friends", "request_sent", "request_received"}
+				return columns, [][]driver.Value{{profileId, "Bob", "", int64(1), time.Now(), int64(0), false, true, false, true}}
+			})
+
+			useCase := newTestUseCase(testRepositories{User: repository.NewUserRepository(db)})
+
+			profile, err := useCase.UserProfile(context.Background(), profileId, tt.viewerId)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if profile.FriendshipStatus != tt.want {
+				t.Errorf("status = %q, want %q", profile.FriendshipStatus, tt.want)
+			}
+		})
+	}
+}
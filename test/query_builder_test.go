@@ -0,0 +1,240 @@
+package test
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	"github.com/Dzikuri/openidea-segokuning/internal/repository"
+	"github.com/lib/pq"
+)
+
+func TestEscapeLike(t *testing.T) {
+	cases := map[string]string{
+		"plain":       "plain",
+		"100%":        `100\%`,
+		"snake_case":  `snake\_case`,
+		`back\slash`:  `back\\slash`,
+		"it's":        "it's",
+		`"quoted"`:    `"quoted"`,
+		`%_\`:         `\%\_\\`,
+		"' OR 1=1 --": "' OR 1=1 --",
+		"日本語 100% ok": `日本語 100\% ok`,
+	}
+
+	for input, expected := range cases {
+		if got := repository.EscapeLike(input); got != expected {
+			t.Errorf("EscapeLike(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestQueryBuilderFriendSearchIsParameterized(t *testing.T) {
+	search := `O'Brien' OR '1'='1 100%`
+
+	query := repository.NewQueryBuilder()
+	query.WhereNotEqual("users.id", "user-id")
+	query.WhereLike("users.name", search)
+	limit := query.Arg(5)
+	offset := query.Arg(0)
+
+	condition := query.Condition()
+	if condition != " WHERE users.id <> $1 AND users.name LIKE $2 " {
+		t.Fatalf("unexpected condition %q", condition)
+	}
+
+	if limit != "$3" || offset != "$4" {
+		t.Fatalf("unexpected limit/offset placeholders %q %q", limit, offset)
+	}
+
+	if strings.Contains(condition, "O'Brien") || strings.Contains(condition, "%") {
+		t.Fatalf("search value leaked into query text: %q", condition)
+	}
+
+	expected := []interface{}{"user-id", `%O'Brien' OR '1'='1 100\%%`, 5, 0}
+	if !reflect.DeepEqual(query.Args(), expected) {
+		t.Fatalf("unexpected args %#v", query.Args())
+	}
+}
+
+func TestQueryBuilderPostTagOverlapIsParameterized(t *testing.T) {
+	tags := []string{`a'b`, `c"d`, `e,f`, `{g}`}
+
+	query := repository.NewQueryBuilder()
+	query.WhereLike("posts.content", "_%")
+	query.WhereOverlap("posts.tags", tags)
+
+	condition := query.Condition()
+	if condition != " WHERE posts.content LIKE $1 AND posts.tags && $2::text[] " {
+		t.Fatalf("unexpected condition %q", condition)
+	}
+
+	args := query.Args()
+	if args[0] != `%\_\%%` {
+		t.Fatalf("unexpected search pattern %q", args[0])
+	}
+
+	if !reflect.DeepEqual(args[1], pq.StringArray(tags)) {
+		t.Fatalf("unexpected tag argument %#v", args[1])
+	}
+}
+
+func TestQueryBuilderWithoutConditions(t *testing.T) {
+	query := repository.NewQueryBuilder()
+
+	if query.Condition() != "" {
+		t.Fatalf("expected empty condition, got %q", query.Condition())
+	}
+
+	if len(query.Args()) != 0 {
+		t.Fatalf("expected no args, got %#v", query.Args())
+	}
+}
+
+// placeholders returns the distinct $n numbers used in query, in ascending order.
+func placeholders(query string) []int {
+	seen := map[int]bool{}
+	for _, match := range regexp.MustCompile(`\$(\d+)`).FindAllStringSubmatch(query, -1) {
+		n, _ := strconv.Atoi(match[1])
+		seen[n] = true
+	}
+
+	numbers := make([]int, 0, len(seen))
+	for n := range seen {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	return numbers
+}
+
+// sequence returns 1..n.
+func sequence(n int) []int {
+	numbers := make([]int, n)
+	for i := range numbers {
+		numbers[i] = i + 1
+	}
+	return numbers
+}
+
+// listingResponse answers a count query with total and any other query with rows.
+func listingResponse(total int64, columns []string, rows [][]driver.Value) func(query string) ([]string, [][]driver.Value) {
+	return func(query string) ([]string, [][]driver.Value) {
+		if strings.HasPrefix(query, "SELECT count(*)") {
+			return []string{"count"}, [][]driver.Value{{total}}
+		}
+		return columns, rows
+	}
+}
+
+// assertPlaceholders fails unless query uses exactly $1..$n for its n args.
+func assertPlaceholders(t *testing.T, name string, query recordedQuery) {
+	t.Helper()
+
+	if got := placeholders(query.Query); !reflect.DeepEqual(got, sequence(len(query.Args))) {
+		t.Errorf("%s query uses %v, want exactly %v for its %d args", name, got, sequence(len(query.Args)), len(query.Args))
+	}
+}
+
+// assertCountArgsPrefix fails unless the count query shares the first page args, so the shared $n mean the same values.
+func assertCountArgsPrefix(t *testing.T, count recordedQuery, page recordedQuery) {
+	t.Helper()
+
+	if len(count.Args) > len(page.Args) || !reflect.DeepEqual(count.Args, page.Args[:len(count.Args)]) {
+		t.Errorf("count args %#v are not a prefix of page args %#v", count.Args, page.Args)
+	}
+}
+
+func TestGetFriendListTotal(t *testing.T) {
+	columns := []string{"id", "name", "image_url", "total_friend", "created_at", "total_count"}
+	rows := [][]driver.Value{
+		{"bob", "Bob", "", int64(1), time.Now(), int64(7)},
+		{"bobby", "Bobby", "", int64(2), time.Now(), int64(7)},
+	}
+	db, recorder := newRecordingDB(t, listingResponse(7, columns, rows))
+
+	uc := newTestUseCase(testRepositories{Friend: repository.NewFriendRepository(db)})
+
+	res, err := uc.GetFriendList(context.Background(), model.GetFriendListRequest{UserId: "alice", OnlyFriend: true, Search: "bo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Meta.Total != 7 || res.Meta.Limit != 5 || len(res.Data) != 2 {
+		t.Fatalf("meta = %+v with %d friends, want the total 7 and the default limit 5 for 2 friends", res.Meta, len(res.Data))
+	}
+
+	pages := recorder.Find("SELECT users.id")
+	if len(pages) != 1 {
+		t.Fatalf("ran %d page queries, want 1", len(pages))
+	}
+	assertPlaceholders(t, "page", pages[0])
+
+	if counts := recorder.Find("SELECT count(*)"); len(counts) != 0 {
+		t.Fatalf("counted separately although the page carried the total: %v", counts)
+	}
+}
+
+func TestGetFriendListTotalPastTheEnd(t *testing.T) {
+	db, recorder := newRecordingDB(t, listingResponse(7, nil, nil))
+
+	uc := newTestUseCase(testRepositories{Friend: repository.NewFriendRepository(db)})
+
+	res, err := uc.GetFriendList(context.Background(), model.GetFriendListRequest{UserId: "alice", OnlyFriend: true, Search: "bo", Offset: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Meta.Total != 7 || len(res.Data) != 0 {
+		t.Fatalf("meta = %+v with %d friends, want the total 7 and no friends", res.Meta, len(res.Data))
+	}
+
+	pages := recorder.Find("SELECT users.id")
+	counts := recorder.Find("SELECT count(*)")
+	if len(pages) != 1 || len(counts) != 1 {
+		t.Fatalf("ran %d page and %d count queries, want 1 of each", len(pages), len(counts))
+	}
+
+	assertPlaceholders(t, "page", pages[0])
+	assertPlaceholders(t, "count", counts[0])
+	assertCountArgsPrefix(t, counts[0], pages[0])
+}
+
+func TestPostListTotalPastTheEnd(t *testing.T) {
+	db, recorder := newRecordingDB(t, listingResponse(7, nil, nil))
+
+	uc := newTestUseCase(testRepositories{Post: repository.NewPostRepository(db)})
+
+	res, err := uc.PostList(context.Background(), &model.PostListRequest{
+		UserId:    "alice",
+		Limit:     10,
+		Search:    "go",
+		SearchTag: []string{"golang"},
+		Cursor:    helper.EncodeCursor(time.Now(), "0b6f2a5e-7d1c-4c3e-9a8b-2f4d6e8a1c3b"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Meta.Total != 7 || res.Meta.Limit != 10 || len(res.Data) != 0 {
+		t.Fatalf("meta = %+v with %d posts, want the total 7 and the limit 10 for no posts", res.Meta, len(res.Data))
+	}
+
+	pages := recorder.Find("SELECT\n\tposts.")
+	counts := recorder.Find("SELECT count(*)")
+	if len(pages) != 1 || len(counts) != 1 {
+		t.Fatalf("ran %d page and %d count queries, want 1 of each", len(pages), len(counts))
+	}
+
+	assertPlaceholders(t, "page", pages[0])
+	assertPlaceholders(t, "count", counts[0])
+	assertCountArgsPrefix(t, counts[0], pages[0])
+}
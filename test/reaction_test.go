@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+)
+
+func TestReactionRequestValidate(t *testing.T) {
+	tests := map[model.ReactionType]bool{model.ReactionLike: true, model.ReactionAngry: true, "": false, "dislike": false}
+
+	for reaction, valid := range tests {
+		if err := (model.ReactionRequest{Type: reaction}).Validate(); (err == nil) != valid {
+			t.Errorf("type %q: Validate() = %v, want valid %v", reaction, err, valid)
+		}
+	}
+}
+
+func TestReact(t *testing.T) {
+	tests := []struct {
+		name           string
+		caller         string
+		commentId      string
+		commentDeleted bool
+		blocked        [2]string
+		wantErr        error
+	}{
+		{"post", "carol", "", false, [2]string{}, nil},
+		{"comment", "carol", "comment", false, [2]string{}, nil},
+		{"removed comment", "carol", "comment", true, [2]string{}, model.ErrCommentNotFound},
+		{"post the caller cannot see", "dave", "", false, [2]string{}, model.ErrResNotFound.Error},
+		{"comment on a post the caller cannot see", "dave", "comment", false, [2]string{}, model.ErrResNotFound.Error},
+		{"post author blocked the caller", "carol", "", false, [2]string{"alice", "carol"}, model.ErrBlocked},
+		{"caller blocked the comment author", "carol", "comment", false, [2]string{"carol", "bob"}, model.ErrBlocked},
+		{"comment author blocked on a post reaction", "carol", "", false, [2]string{"bob", "carol"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, _ := newBobComment()
+			posts.Comments["comment"].IsDeleted = tt.commentDeleted
+
+			blocks := newFakeBlockRepository()
+			if tt.blocked != [2]string{} {
+				blocks.Blocks[tt.blocked] = model.BlockTypeBlock
+			}
+
+			reactions := &fakeReactionRepository{}
+			useCase := newTestUseCase(testRepositories{Post: posts, Block: blocks, Reaction: reactions})
+
+			request := model.ReactionRequest{UserId: tt.caller, Type: model.ReactionLike}
+			if tt.commentId != "" {
+				request.CommentId = tt.commentId
+			} else {
+				request.PostId = "post"
+			}
+
+			_, err := useCase.React(context.Background(), request)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			if stored := len(reactions.Reacted) == 1; stored != (tt.wantErr == nil) {
+				t.Errorf("stored = %v, want %v", stored, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordedQuery is a statement the repository sent through the recording driver.
+type recordedQuery struct {
+	Query string
+	Args  []driver.Value
+}
+
+// recordingDB records every statement and answers queries with the rows Respond returns.
+type recordingDB struct {
+	mu      sync.Mutex
+	Queries []recordedQuery
+	// Respond returns the columns and rows of query, a nil Respond answers with no rows
+	Respond func(query string) ([]string, [][]driver.Value)
+}
+
+// Find returns the recorded queries starting with prefix, ignoring leading whitespace.
+func (r *recordingDB) Find(prefix string) []recordedQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found []recordedQuery
+	for _, query := range r.Queries {
+		if strings.HasPrefix(strings.TrimSpace(query.Query), prefix) {
+			found = append(found, query)
+		}
+	}
+	return found
+}
+
+func (r *recordingDB) record(query string, args []driver.NamedValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	r.Queries = append(r.Queries, recordedQuery{Query: query, Args: values})
+}
+
+var recordingDBs sync.Map
+
+func init() {
+	sql.Register("recording", recordingDriver{})
+}
+
+// newRecordingDB opens a *sql.DB for the real repositories, nothing is executed.
+func newRecordingDB(t *testing.T, respond func(query string) ([]string, [][]driver.Value)) (*sql.DB, *recordingDB) {
+	recorder := &recordingDB{Respond: respond}
+	recordingDBs.Store(t.Name(), recorder)
+
+	db, err := sql.Open("recording", t.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		recordingDBs.Delete(t.Name())
+	})
+
+	return db, recorder
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	recorder, ok := recordingDBs.Load(name)
+	if !ok {
+		return nil, errors.New("no recording db named " + name)
+	}
+	return &recordingConn{db: recorder.(*recordingDB)}, nil
+}
+
+type recordingConn struct {
+	db *recordingDB
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("recording driver does not prepare statements")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, args)
+
+	rows := &recordingRows{}
+	if c.db.Respond != nil {
+		rows.columns, rows.values = c.db.Respond(query)
+	}
+	return rows, nil
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+
+func (r *recordingRows) Close() error { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
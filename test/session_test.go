@@ -0,0 +1,38 @@
+package test
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLogoutIsIdempotent(t *testing.T) {
+	sessions := newFakeSessions()
+	sessions.Start("user", "phone")
+	sessions.Start("user", "laptop")
+
+	sessionRepository := &fakeSessionRepository{Sessions: sessions}
+	uc := newTestUseCase(testRepositories{Session: sessionRepository})
+
+	for i := 0; i < 2; i++ {
+		if err := uc.Logout(context.Background(), "phone", "user"); err != nil {
+			t.Fatalf("logout %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	active, err := uc.IsSessionActive(context.Background(), "phone", "user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active {
+		t.Error("session still active after logout")
+	}
+
+	active, _ = uc.IsSessionActive(context.Background(), "laptop", "user")
+	if !active {
+		t.Error("logout revoked another session")
+	}
+
+	if len(sessionRepository.Touched) != 0 {
+		t.Errorf("checking a session touched it: %v", sessionRepository.Touched)
+	}
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+)
+
+func TestGenerateRefreshTokenStoresOnlyHash(t *testing.T) {
+	token, hash, err := helper.GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if token == hash {
+		t.Fatalf("hash equals token")
+	}
+
+	if len(hash) != 64 {
+		t.Errorf("hash length = %d, want 64", len(hash))
+	}
+
+	if helper.HashToken(token) != hash {
+		t.Errorf("HashToken(token) = %q, want %q", helper.HashToken(token), hash)
+	}
+
+	other, _, err := helper.GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if other == token {
+		t.Errorf("two generated tokens are equal")
+	}
+}
+
+func TestGenerateVerificationCode(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		code, err := helper.GenerateVerificationCode(6)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(code) != 6 {
+			t.Fatalf("code %q has length %d, want 6", code, len(code))
+		}
+
+		for _, r := range code {
+			if r < '0' || r > '9' {
+				t.Fatalf("code %q is not numeric", code)
+			}
+		}
+	}
+}
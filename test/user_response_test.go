@@ -0,0 +1,125 @@
+package test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+const passwordHash = "$2a$10$7EqJtq98hPqEX7fNZaFWoOhi5BWX4Z6OkPpY8z9d7hjxk1Zw3rQxK"
+
+func testUser() *model.User {
+	return &model.User{
+		Id:        uuid.NewV4(),
+		Phone:     sql.NullString{String: "+6281234567890", Valid: true},
+		Email:     sql.NullString{String: "user@example.com", Valid: true},
+		Name:      "user name",
+		ImageUrl:  "https://example.com/a.jpg",
+		Password:  passwordHash,
+		UpdatedAt: time.Now(),
+		CreatedAt: time.Now(),
+	}
+}
+
+// hasPasswordKey reports whether any object in the decoded JSON value has a password key.
+func hasPasswordKey(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if strings.EqualFold(key, "password") || hasPasswordKey(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if hasPasswordKey(child) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func TestResponsesNeverSerializePassword(t *testing.T) {
+	user := testUser()
+
+	responses := map[string]interface{}{
+		"User":                    user,
+		"UserResponse":            model.NewUserResponse(user),
+		"UserAuthResponse":        model.UserAuthResponse{},
+		"UserProfileResponse":     model.UserProfileResponse{},
+		"FriendResponse":          model.FriendResponse{},
+		"FriendRequestResponse":   model.FriendRequestResponse{User: &model.FriendResponse{}},
+		"BlockResponse":           model.BlockResponse{},
+		"PostResponse":            model.PostResponse{},
+		"PostHistoryResponse":     model.PostHistoryResponse{},
+		"PostCommentResponse":     model.PostCommentResponse{},
+		"PostCommentUserResponse": model.PostCommentUserResponse{Replies: []model.PostCommentUserResponse{{}}},
+		"PostListResponse":        model.PostListResponse{Comments: []model.PostCommentUserResponse{{}}},
+		"PostDetailResponse":      model.PostDetailResponse{},
+		"ReactionResponse":        model.ReactionResponse{},
+		"SessionResponse":         model.SessionResponse{},
+		"UserDeleteResponse":      model.UserDeleteResponse{},
+		"UserExportResponse":      model.UserExportResponse{Friends: []model.UserExportFriend{{}}, Posts: []model.UserExportPost{{}}, Comments: []model.UserExportComment{{}}},
+		"Response":                model.Response[any]{Data: model.NewUserResponse(user)},
+		"PaginateResponse":        model.PaginateResponse[*model.UserResponse]{Data: []*model.UserResponse{model.NewUserResponse(user)}},
+		"ResponseError":           model.ResponseError{},
+	}
+
+	for name, response := range responses {
+		data, err := json.Marshal(response)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if strings.Contains(string(data), passwordHash) {
+			t.Errorf("%s leaks the password hash: %s", name, data)
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		if hasPasswordKey(decoded) {
+			t.Errorf("%s has a password field: %s", name, data)
+		}
+	}
+}
+
+func TestNewUserResponseKeepsPublicFields(t *testing.T) {
+	user := testUser()
+
+	response := model.NewUserResponse(user)
+
+	if response.Id != user.Id || response.Name != user.Name || response.ImageUrl != user.ImageUrl {
+		t.Errorf("response = %+v", response)
+	}
+
+	if response.Email != user.Email.String || response.Phone != user.Phone.String {
+		t.Errorf("email = %q phone = %q", response.Email, response.Phone)
+	}
+}
+
+func TestUserExportResponseKeepsEmptyLists(t *testing.T) {
+	data, err := json.Marshal(model.UserExportResponse{
+		Friends:  make([]model.UserExportFriend, 0),
+		Posts:    make([]model.UserExportPost, 0),
+		Comments: make([]model.UserExportComment, 0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []string{`"friends":[]`, `"posts":[]`, `"comments":[]`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("export %s is missing %s", data, key)
+		}
+	}
+}
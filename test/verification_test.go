@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dzikuri/openidea-segokuning/internal/helper"
+	"github.com/Dzikuri/openidea-segokuning/internal/model"
+	uuid "github.com/satori/go.uuid"
+)
+
+// fakeVerificationRepository keeps one pending verification and claims attempts under a lock, like the UPDATE it stands for.
+type fakeVerificationRepository struct {
+	mu           sync.Mutex
+	Verification *model.CredentialVerification
+	Confirmed    int
+}
+
+func (r *fakeVerificationRepository) CreateCredentialVerification(ctx context.Context, request model.CredentialVerification) (*model.CredentialVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	verification := request
+	r.Verification = &verification
+	return &verification, nil
+}
+
+func (r *fakeVerificationRepository) ClaimVerificationAttempt(ctx context.Context, userId string, credentialType model.UserCredentialType) (*model.CredentialVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Verification == nil {
+		return nil, model.ErrVerificationNotFound
+	}
+
+	if r.Verification.Attempts >= model.VerificationCodeMaxAttempts {
+		return nil, model.ErrVerificationAttemptsExceeded
+	}
+
+	r.Verification.Attempts++
+	claimed := *r.Verification
+	return &claimed, nil
+}
+
+func (r *fakeVerificationRepository) ConfirmCredentialVerification(ctx context.Context, verification *model.CredentialVerification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Confirmed++
+	return nil
+}
+
+func TestUserLinkConfirmConcurrentGuessesAreBounded(t *testing.T) {
+	verifications := &fakeVerificationRepository{Verification: &model.CredentialVerification{
+		CredentialType: model.Email,
+		CodeHash:       helper.HashToken("123456"),
+		ExpiresAt:      time.Now().Add(model.VerificationCodeTTL),
+	}}
+	useCase := newTestUseCase(testRepositories{Verification: verifications})
+
+	var mu sync.Mutex
+	results := map[error]int{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := useCase.UserLinkConfirm(context.Background(), &model.UserLinkConfirmRequest{Id: uuid.NewV4(), CredentialType: model.Email, Code: "000000"})
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[err]++
+		}()
+	}
+	wg.Wait()
+
+	if results[model.ErrVerificationCodeInvalid] != model.VerificationCodeMaxAttempts {
+		t.Errorf("%d codes compared, want %d", results[model.ErrVerificationCodeInvalid], model.VerificationCodeMaxAttempts)
+	}
+
+	if results[model.ErrVerificationAttemptsExceeded] != 50-model.VerificationCodeMaxAttempts {
+		t.Errorf("%d guesses rejected, want %d", results[model.ErrVerificationAttemptsExceeded], 50-model.VerificationCodeMaxAttempts)
+	}
+
+	err := useCase.UserLinkConfirm(context.Background(), &model.UserLinkConfirmRequest{Id: uuid.NewV4(), CredentialType: model.Email, Code: "123456"})
+	if !errors.Is(err, model.ErrVerificationAttemptsExceeded) {
+		t.Errorf("right code after the limit: err = %v, want %v", err, model.ErrVerificationAttemptsExceeded)
+	}
+}
+
+func TestUserLinkConfirmRightCode(t *testing.T) {
+	verifications := &fakeVerificationRepository{Verification: &model.CredentialVerification{
+		CredentialType: model.Email,
+		CodeHash:       helper.HashToken("123456"),
+		ExpiresAt:      time.Now().Add(model.VerificationCodeTTL),
+	}}
+	useCase := newTestUseCase(testRepositories{Verification: verifications})
+
+	err := useCase.UserLinkConfirm(context.Background(), &model.UserLinkConfirmRequest{Id: uuid.NewV4(), CredentialType: model.Email, Code: "123456"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if verifications.Confirmed != 1 {
+		t.Errorf("confirmed %d times, want 1", verifications.Confirmed)
+	}
+}
+
+func TestReissueVerification(t *testing.T) {
+	now := time.Now()
+
+	attempts, windowStart, err := model.ReissueVerification(nil, now)
+	if err != nil || attempts != 0 || !windowStart.Equal(now) {
+		t.Errorf("first code: attempts = %d, windowStart = %v, err = %v", attempts, windowStart, err)
+	}
+
+	recent := &model.CredentialVerification{Attempts: 3, SentAt: now.Add(-10 * time.Second), CreatedAt: now.Add(-10 * time.Second)}
+	_, _, err = model.ReissueVerification(recent, now)
+	if !errors.Is(err, model.ErrVerificationResendTooSoon) {
+		t.Errorf("resend within interval: err = %v, want %v", err, model.ErrVerificationResendTooSoon)
+	}
+
+	inWindow := &model.CredentialVerification{Attempts: 3, SentAt: now.Add(-2 * model.VerificationResendInterval), CreatedAt: now.Add(-10 * time.Minute)}
+	attempts, windowStart, err = model.ReissueVerification(inWindow, now)
+	if err != nil || attempts != 3 || !windowStart.Equal(inWindow.CreatedAt) {
+		t.Errorf("resend within window: attempts = %d, windowStart = %v, err = %v, want attempts kept", attempts, windowStart, err)
+	}
+
+	expired := &model.CredentialVerification{Attempts: 5, SentAt: now.Add(-2 * model.VerificationAttemptWindow), CreatedAt: now.Add(-2 * model.VerificationAttemptWindow)}
+	attempts, windowStart, err = model.ReissueVerification(expired, now)
+	if err != nil || attempts != 0 || !windowStart.Equal(now) {
+		t.Errorf("resend after window: attempts = %d, windowStart = %v, err = %v, want a new window", attempts, windowStart, err)
+	}
+}